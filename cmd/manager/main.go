@@ -9,8 +9,12 @@ import (
 
 	"github.com/redhat-cop/image-security/pkg/apis"
 	"github.com/redhat-cop/image-security/pkg/controller"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/cleanup"
+	"github.com/redhat-cop/image-security/pkg/sigstore"
+	"github.com/redhat-cop/image-security/pkg/webhook"
 	"github.com/redhat-cop/image-security/version"
 
+	buildv1 "github.com/openshift/api/build/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	ossecurityv1 "github.com/openshift/api/security/v1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -36,6 +40,12 @@ var (
 	metricsPort         int32 = 8383
 	operatorMetricsPort int32 = 8686
 )
+
+// Webhooks are served on the below port using the certificates found in webhookCertDir.
+var (
+	webhookPort    = 9443
+	webhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+)
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -54,6 +64,8 @@ func main() {
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
+	enableWebhooks := pflag.Bool("enable-webhooks", true, "Serve the conversion and admission webhooks. Requires serving certificates in "+webhookCertDir)
+
 	pflag.Parse()
 
 	// Use a zap logr.Logger implementation. If none of the zap
@@ -94,6 +106,8 @@ func main() {
 		Namespace:          "",
 		MapperProvider:     restmapper.NewDynamicRESTMapper,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:               webhookPort,
+		CertDir:            webhookCertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -119,12 +133,37 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := buildv1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	// Setup all Webhooks
+	if *enableWebhooks {
+		if err := webhook.AddToManager(mgr); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
+	// Serve the sigstore of the operator
+	if err := sigstore.Add(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Delete finished requests once they expire
+	if err := cleanup.Add(mgr); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	if err = serveCRMetrics(cfg); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
 	}
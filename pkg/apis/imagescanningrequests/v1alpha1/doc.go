@@ -0,0 +1,4 @@
+// Package v1alpha1 contains API Schema definitions for the imagescanningrequests v1alpha1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=imagescanningrequests.cop.redhat.com
+package v1alpha1
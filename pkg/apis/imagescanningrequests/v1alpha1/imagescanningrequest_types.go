@@ -0,0 +1,58 @@
+package v1alpha1
+
+import (
+	images "github.com/redhat-cop/image-security/pkg/controller/images"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageScanningRequestSpec defines the desired state of ImageScanningRequest
+// +k8s:openapi-gen=true
+type ImageScanningRequestSpec struct {
+	ContainerImage *kapi.ObjectReference      `json:"containerImage"`
+	PullSecret     *kapi.LocalObjectReference `json:"pullSecret,omitempty"`
+}
+
+// ImageScanningResults summarizes the outcome of a completed scan
+// +k8s:openapi-gen=true
+type ImageScanningResults struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// ImageScanningRequestStatus defines the observed state of ImageScanningRequest
+// +k8s:openapi-gen=true
+type ImageScanningRequestStatus struct {
+	Conditions   []images.ImageExecutionCondition `json:"conditions,omitempty"`
+	Phase        images.ImageExecutionPhase       `json:"phase,omitempty"`
+	ScannedImage string                           `json:"scannedImage,omitempty"`
+	Results      *ImageScanningResults            `json:"results,omitempty"`
+	StartTime    string                           `json:"startTime,omitempty"`
+	EndTime      string                           `json:"endTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageScanningRequest is the Schema for the imagescanningrequests API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=imagescanningrequests,scope=Namespaced
+type ImageScanningRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageScanningRequestSpec   `json:"spec,omitempty"`
+	Status ImageScanningRequestStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageScanningRequestList contains a list of ImageScanningRequest
+type ImageScanningRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageScanningRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageScanningRequest{}, &ImageScanningRequestList{})
+}
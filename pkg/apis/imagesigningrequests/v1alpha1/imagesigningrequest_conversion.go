@@ -0,0 +1,266 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	images "github.com/redhat-cop/image-security/pkg/controller/images"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// timeLayout is the layout produced by time.Time.String(), which is how v1alpha1 timestamps are stored
+const timeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// specAnnotation holds the fields of a v1alpha2 spec that cannot be represented in v1alpha1 so they survive a
+// round trip through this version
+const specAnnotation = "imagesigningrequests.cop.redhat.com/v1alpha2-spec"
+
+// statusAnnotation holds the fields of a v1alpha2 status that cannot be represented in v1alpha1, along with the
+// reasons of its conditions, so they survive a round trip through this version
+const statusAnnotation = "imagesigningrequests.cop.redhat.com/v1alpha2-status"
+
+// ConvertTo converts this ImageSigningRequest to the Hub version (v1alpha2)
+func (src *ImageSigningRequest) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha2.ImageSigningRequest)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = v1alpha2.ImageSigningRequestSpec{}
+	if preserved, ok := src.Annotations[specAnnotation]; ok {
+		if err := json.Unmarshal([]byte(preserved), &dst.Spec); err != nil {
+			return err
+		}
+	}
+
+	extraStatus := v1alpha2.ImageSigningRequestStatus{}
+	if preserved, ok := src.Annotations[statusAnnotation]; ok {
+		if err := json.Unmarshal([]byte(preserved), &extraStatus); err != nil {
+			return err
+		}
+	}
+
+	_, hasSpec := src.Annotations[specAnnotation]
+	_, hasStatus := src.Annotations[statusAnnotation]
+	if hasSpec || hasStatus {
+		dst.Annotations = map[string]string{}
+		for key, value := range src.Annotations {
+			if key != specAnnotation && key != statusAnnotation {
+				dst.Annotations[key] = value
+			}
+		}
+	}
+
+	dst.Spec.ContainerImage = src.Spec.ContainerImage
+	dst.Spec.PullSecret = src.Spec.PullSecret
+	dst.Spec.SigningKeySecretName = src.Spec.SigningKeySecretName
+	dst.Spec.SigningKeySignBy = src.Spec.SigningKeySignBy
+
+	dst.Status = extraStatus
+	dst.Status.Phase = src.Status.Phase
+	dst.Status.SignedImage = src.Status.SignedImage
+	dst.Status.UnsignedImage = src.Status.UnsignedImage
+	dst.Status.StartTime = parseTime(src.Status.StartTime)
+	dst.Status.EndTime = parseTime(src.Status.EndTime)
+
+	// v1alpha1 conditions are an append-only log. The log becomes the history and the latest entry of
+	// each type becomes the current condition.
+	dst.Status.Conditions = nil
+	dst.Status.History = nil
+	for _, condition := range src.Status.Conditions {
+		converted := v1alpha2.ImageSigningCondition{
+			Type:    condition.Type,
+			Status:  condition.Status,
+			Message: condition.Message,
+		}
+		if lastTransitionTime := parseTime(condition.LastTransitionTime); lastTransitionTime != nil {
+			converted.LastTransitionTime = *lastTransitionTime
+		}
+		dst.Status.History = append(dst.Status.History, converted)
+
+		replaced := false
+		for i := range dst.Status.Conditions {
+			if dst.Status.Conditions[i].Type == converted.Type {
+				dst.Status.Conditions[i] = converted
+				replaced = true
+			}
+		}
+		if !replaced {
+			dst.Status.Conditions = append(dst.Status.Conditions, converted)
+		}
+	}
+
+	// Conditions left untouched since the request was converted from v1alpha2 keep their reasons
+	if sameConditions(dst.Status.History, convertedConditions(extraStatus)) {
+		dst.Status.History = extraStatus.History
+		dst.Status.Conditions = extraStatus.Conditions
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha2) to this version
+func (dst *ImageSigningRequest) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha2.ImageSigningRequest)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	extraSpec, err := preservedSpec(src.Spec)
+	if err != nil {
+		return err
+	}
+
+	extraStatus, err := preservedStatus(src.Status)
+	if err != nil {
+		return err
+	}
+
+	if extraSpec != "" || extraStatus != "" {
+		dst.Annotations = map[string]string{}
+		for key, value := range src.Annotations {
+			dst.Annotations[key] = value
+		}
+		if extraSpec != "" {
+			dst.Annotations[specAnnotation] = extraSpec
+		}
+		if extraStatus != "" {
+			dst.Annotations[statusAnnotation] = extraStatus
+		}
+	}
+
+	dst.Spec.ContainerImage = src.Spec.ContainerImage
+	dst.Spec.PullSecret = src.Spec.PullSecret
+	dst.Spec.SigningKeySecretName = src.Spec.SigningKeySecretName
+	dst.Spec.SigningKeySignBy = src.Spec.SigningKeySignBy
+
+	dst.Status.Phase = src.Status.Phase
+	dst.Status.SignedImage = src.Status.SignedImage
+	dst.Status.UnsignedImage = src.Status.UnsignedImage
+	dst.Status.StartTime = formatTime(src.Status.StartTime)
+	dst.Status.EndTime = formatTime(src.Status.EndTime)
+
+	dst.Status.Conditions = nil
+	for _, condition := range convertedConditions(src.Status) {
+		dst.Status.Conditions = append(dst.Status.Conditions, images.ImageExecutionCondition{
+			Type:               condition.Type,
+			Status:             condition.Status,
+			Message:            condition.Message,
+			LastTransitionTime: formatTime(&condition.LastTransitionTime),
+		})
+	}
+
+	return nil
+}
+
+// preservedSpec serializes the fields of a v1alpha2 spec that v1alpha1 has no place for. An empty string is
+// returned when every field is represented in v1alpha1.
+func preservedSpec(spec v1alpha2.ImageSigningRequestSpec) (string, error) {
+	extra := spec.DeepCopy()
+	extra.ContainerImage = nil
+	extra.PullSecret = nil
+	extra.SigningKeySecretName = ""
+	extra.SigningKeySignBy = ""
+
+	if reflect.DeepEqual(*extra, v1alpha2.ImageSigningRequestSpec{}) {
+		return "", nil
+	}
+
+	data, err := json.Marshal(extra)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// preservedStatus serializes the fields of a v1alpha2 status that v1alpha1 has no place for, along with the
+// conditions whose reasons v1alpha1 cannot hold. An empty string is returned when every field is represented in
+// v1alpha1.
+func preservedStatus(status v1alpha2.ImageSigningRequestStatus) (string, error) {
+	extra := status.DeepCopy()
+	extra.Phase = ""
+	extra.SignedImage = ""
+	extra.UnsignedImage = ""
+	extra.StartTime = nil
+	extra.EndTime = nil
+
+	if !hasReasons(status.Conditions) && !hasReasons(status.History) {
+		extra.Conditions = nil
+		extra.History = nil
+	}
+
+	if reflect.DeepEqual(*extra, v1alpha2.ImageSigningRequestStatus{}) {
+		return "", nil
+	}
+
+	data, err := json.Marshal(extra)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// convertedConditions returns the conditions of a v1alpha2 status that become v1alpha1 conditions. The history is
+// preferred as it matches the append-only semantics of v1alpha1 conditions.
+func convertedConditions(status v1alpha2.ImageSigningRequestStatus) []v1alpha2.ImageSigningCondition {
+	if len(status.History) == 0 {
+		return status.Conditions
+	}
+
+	return status.History
+}
+
+func hasReasons(conditions []v1alpha2.ImageSigningCondition) bool {
+	for _, condition := range conditions {
+		if condition.Reason != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameConditions reports whether two lists of conditions are the same apart from their reasons, which v1alpha1
+// conditions do not have
+func sameConditions(a []v1alpha2.ImageSigningCondition, b []v1alpha2.ImageSigningCondition) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Status != b[i].Status || a[i].Message != b[i].Message || !a[i].LastTransitionTime.Time.Equal(b[i].LastTransitionTime.Time) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseTime reads a v1alpha1 timestamp. The zero time was historically used in place of an unset value.
+func parseTime(value string) *metav1.Time {
+	// Drop the monotonic clock reading that time.Time.String() may append
+	if i := strings.Index(value, " m="); i != -1 {
+		value = value[:i]
+	}
+
+	parsed, err := time.Parse(timeLayout, value)
+	if err != nil || parsed.IsZero() {
+		return nil
+	}
+
+	converted := metav1.NewTime(parsed)
+	return &converted
+}
+
+// formatTime renders a timestamp the way v1alpha1 has always stored it
+func formatTime(value *metav1.Time) string {
+	if value == nil {
+		return time.Time{}.String()
+	}
+
+	return value.Time.String()
+}
@@ -0,0 +1,249 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	images "github.com/redhat-cop/image-security/pkg/controller/images"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTime(value string) metav1.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return metav1.NewTime(parsed)
+}
+
+func newTimePointer(value string) *metav1.Time {
+	converted := newTime(value)
+	return &converted
+}
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+// v1alpha2Request uses every field that v1alpha1 has no place for
+func v1alpha2Request() *v1alpha2.ImageSigningRequest {
+	return &v1alpha2.ImageSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "dotnet-app",
+			Namespace:   "signing-test",
+			Generation:  2,
+			Annotations: map[string]string{"example.com/team": "dotnet"},
+		},
+		Spec: v1alpha2.ImageSigningRequestSpec{
+			ContainerImage:          &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "dotnet-example:latest"},
+			PullSecret:              &kapi.LocalObjectReference{Name: "pull-secret"},
+			SigningKeyRef:           &kapi.LocalObjectReference{Name: "release"},
+			Executor:                v1alpha2.ExecutorInProcess,
+			Platforms:               []string{"linux/amd64", "linux/arm64/v8"},
+			ActiveDeadlineSeconds:   int64Pointer(600),
+			RetryPolicy:             &v1alpha2.ImageSigningRetryPolicy{MaxAttempts: 3, Backoff: &metav1.Duration{Duration: 30 * time.Second}},
+			Force:                   true,
+			TTLSecondsAfterFinished: int64Pointer(3600),
+			Build:                   &v1alpha2.ImageSigningBuild{Name: "dotnet-example-4", BuildConfig: "dotnet-example", Commit: "0a1b2c3d"},
+		},
+		Status: v1alpha2.ImageSigningRequestStatus{
+			ObservedGeneration: 2,
+			Conditions: []v1alpha2.ImageSigningCondition{
+				{Type: images.ImageExecutionConditionInitialization, Status: kapi.ConditionTrue, Reason: "Initialized", Message: "Signing Pod Launched", LastTransitionTime: newTime("2020-01-02T03:04:05Z")},
+				{Type: images.ImageExecutionConditionFinished, Status: kapi.ConditionTrue, Reason: "Signed", Message: "Image Signed", LastTransitionTime: newTime("2020-01-02T03:05:06Z")},
+			},
+			History: []v1alpha2.ImageSigningCondition{
+				{Type: images.ImageExecutionConditionInitialization, Status: kapi.ConditionTrue, Reason: "Initialized", Message: "Signing Pod Launched", LastTransitionTime: newTime("2020-01-02T03:04:05Z")},
+				{Type: images.ImageExecutionConditionFinished, Status: kapi.ConditionTrue, Reason: "Signed", Message: "Image Signed", LastTransitionTime: newTime("2020-01-02T03:05:06Z")},
+			},
+			Phase:             images.PhaseCompleted,
+			RequestedImage:    "image-registry.openshift-image-registry.svc:5000/signing-test/dotnet-example:latest",
+			ResolvedImage:     "image-registry.openshift-image-registry.svc:5000/signing-test/dotnet-example@sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			SignedImage:       "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			UnsignedImage:     "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			SignatureArtifact: "quay.io/redhat-cop/dotnet-example:sha256-5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270.sig",
+			Platforms: []v1alpha2.ImageSigningPlatformStatus{
+				{Platform: "linux/amd64", Digest: "sha256:1111111111111111111111111111111111111111111111111111111111111111", Signed: true},
+			},
+			Images: []v1alpha2.ImageSigningImageStatus{
+				{ContainerImage: kapi.ObjectReference{Kind: "ImageStreamTag", Name: "dotnet-example:latest"}, Phase: images.PhaseCompleted, Message: "Image Signed"},
+			},
+			Attempts: []v1alpha2.ImageSigningAttempt{
+				{Pod: "image-management/uid-1", StartTime: newTimePointer("2020-01-02T03:04:05Z"), EndTime: newTimePointer("2020-01-02T03:04:35Z"), ExitCode: 1, Reason: "Error"},
+				{Pod: "image-management/uid-2", StartTime: newTimePointer("2020-01-02T03:04:45Z")},
+			},
+			Failure:   &v1alpha2.ImageSigningFailure{Pod: "image-management/uid-1", ExitCode: 1, Reason: "Error", Message: "Error pulling image", LogTail: "Error pulling image"},
+			StartTime: newTimePointer("2020-01-02T03:04:05Z"),
+			EndTime:   newTimePointer("2020-01-02T03:05:06Z"),
+		},
+	}
+}
+
+func TestConvertV1alpha2RoundTrip(t *testing.T) {
+
+	original := v1alpha2Request()
+
+	converted := &ImageSigningRequest{}
+	if err := converted.ConvertFrom(original.DeepCopy()); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	if _, ok := converted.Annotations[specAnnotation]; !ok {
+		t.Errorf("ConvertFrom() did not preserve the spec in the %s annotation", specAnnotation)
+	}
+	if _, ok := converted.Annotations[statusAnnotation]; !ok {
+		t.Errorf("ConvertFrom() did not preserve the status in the %s annotation", statusAnnotation)
+	}
+	if converted.Status.StartTime != "2020-01-02 03:04:05 +0000 UTC" {
+		t.Errorf("ConvertFrom() startTime = %q", converted.Status.StartTime)
+	}
+	if len(converted.Status.Conditions) != len(original.Status.History) {
+		t.Errorf("ConvertFrom() converted %d conditions, want the %d entries of the history", len(converted.Status.Conditions), len(original.Status.History))
+	}
+
+	roundTripped := &v1alpha2.ImageSigningRequest{}
+	if err := converted.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+
+	if !equality.Semantic.DeepEqual(roundTripped.Spec, original.Spec) {
+		t.Errorf("spec after round trip = %+v, want %+v", roundTripped.Spec, original.Spec)
+	}
+	if !equality.Semantic.DeepEqual(roundTripped.Status, original.Status) {
+		t.Errorf("status after round trip = %+v, want %+v", roundTripped.Status, original.Status)
+	}
+	if !equality.Semantic.DeepEqual(roundTripped.Annotations, original.Annotations) {
+		t.Errorf("annotations after round trip = %v, want %v", roundTripped.Annotations, original.Annotations)
+	}
+}
+
+func TestConvertV1alpha1UpdateKeepsV1alpha2Fields(t *testing.T) {
+
+	original := v1alpha2Request()
+
+	converted := &ImageSigningRequest{}
+	if err := converted.ConvertFrom(original.DeepCopy()); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	// A v1alpha1 client appends to the condition log and changes the phase
+	converted.Status.Phase = images.PhaseFailed
+	converted.Status.Conditions = append(converted.Status.Conditions, images.ImageExecutionCondition{
+		Type:               images.ImageExecutionConditionFinished,
+		Status:             kapi.ConditionFalse,
+		Message:            "Cancelled",
+		LastTransitionTime: "2020-01-02 03:06:07 +0000 UTC",
+	})
+
+	updated := &v1alpha2.ImageSigningRequest{}
+	if err := converted.ConvertTo(updated); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+
+	if updated.Status.Phase != images.PhaseFailed {
+		t.Errorf("phase = %q, want the phase set through v1alpha1", updated.Status.Phase)
+	}
+	if len(updated.Status.History) != 3 {
+		t.Errorf("history has %d entries, want 3", len(updated.Status.History))
+	}
+	if len(updated.Status.Attempts) != 2 || updated.Status.Failure == nil || len(updated.Status.Images) != 1 || len(updated.Status.Platforms) != 1 {
+		t.Errorf("v1alpha2 only status fields were lost: %+v", updated.Status)
+	}
+	if updated.Status.ObservedGeneration != 2 || updated.Status.ResolvedImage != original.Status.ResolvedImage || updated.Status.SignatureArtifact != original.Status.SignatureArtifact {
+		t.Errorf("v1alpha2 only status fields were lost: %+v", updated.Status)
+	}
+	if updated.Spec.SigningKeyRef == nil || updated.Spec.Build == nil || updated.Spec.RetryPolicy == nil {
+		t.Errorf("v1alpha2 only spec fields were lost: %+v", updated.Spec)
+	}
+	if _, ok := updated.Annotations[statusAnnotation]; ok {
+		t.Errorf("ConvertTo() left the %s annotation in place", statusAnnotation)
+	}
+}
+
+func TestConvertV1alpha1RoundTrip(t *testing.T) {
+
+	original := &ImageSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "dotnet-app", Namespace: "signing-test"},
+		Spec: ImageSigningRequestSpec{
+			ContainerImage:       &kapi.ObjectReference{Kind: "ContainerRepository", Name: "quay.io/redhat-cop/image-scanning-signing-service:latest"},
+			SigningKeySecretName: "release-key",
+			SigningKeySignBy:     "release@example.com",
+		},
+		Status: ImageSigningRequestStatus{
+			Conditions: []images.ImageExecutionCondition{
+				{Type: images.ImageExecutionConditionInitialization, Status: kapi.ConditionTrue, Message: "Signing Pod Launched", LastTransitionTime: "2020-01-02 03:04:05.123456789 +0000 UTC m=+12.500000001"},
+				{Type: images.ImageExecutionConditionFinished, Status: kapi.ConditionFalse, Message: "Signing", LastTransitionTime: "2020-01-02 03:04:06 +0000 UTC"},
+				{Type: images.ImageExecutionConditionFinished, Status: kapi.ConditionTrue, Message: "Image Signed", LastTransitionTime: "2020-01-02 03:05:06 +0000 UTC"},
+			},
+			Phase:         images.PhaseCompleted,
+			SignedImage:   "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			UnsignedImage: "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+			StartTime:     "2020-01-02 03:04:05.123456789 +0000 UTC m=+12.500000001",
+			// Unset timestamps were historically stored as the zero time
+			EndTime: "0001-01-01 00:00:00 +0000 UTC",
+		},
+	}
+
+	converted := &v1alpha2.ImageSigningRequest{}
+	if err := original.DeepCopy().ConvertTo(converted); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+
+	if converted.Status.StartTime == nil || !converted.Status.StartTime.Time.Equal(time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.UTC)) {
+		t.Errorf("startTime = %v, want 2020-01-02 03:04:05.123456789 UTC", converted.Status.StartTime)
+	}
+	if converted.Status.EndTime != nil {
+		t.Errorf("endTime = %v, want the zero time to be unset", converted.Status.EndTime)
+	}
+	if len(converted.Status.History) != 3 {
+		t.Errorf("history has %d entries, want the 3 entries of the condition log", len(converted.Status.History))
+	}
+	if len(converted.Status.Conditions) != 2 {
+		t.Fatalf("conditions has %d entries, want the latest of each of the 2 types", len(converted.Status.Conditions))
+	}
+	if finished := converted.Status.Conditions[1]; finished.Status != kapi.ConditionTrue || finished.Message != "Image Signed" {
+		t.Errorf("finished condition = %+v, want the latest entry of its type", finished)
+	}
+	if converted.Spec.SigningKeySecretName != "release-key" || converted.Spec.SigningKeySignBy != "release@example.com" || converted.Spec.ContainerImage == nil {
+		t.Errorf("spec = %+v, want the v1alpha1 spec", converted.Spec)
+	}
+
+	roundTripped := &ImageSigningRequest{}
+	if err := roundTripped.ConvertFrom(converted); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+
+	if len(roundTripped.Annotations) != 0 {
+		t.Errorf("annotations = %v, want none for a request without v1alpha2 only fields", roundTripped.Annotations)
+	}
+	if !equality.Semantic.DeepEqual(roundTripped.Spec, original.Spec) {
+		t.Errorf("spec after round trip = %+v, want %+v", roundTripped.Spec, original.Spec)
+	}
+
+	// The monotonic clock reading is dropped, every other part of a timestamp is kept
+	if roundTripped.Status.StartTime != "2020-01-02 03:04:05.123456789 +0000 UTC" {
+		t.Errorf("startTime after round trip = %q", roundTripped.Status.StartTime)
+	}
+	if roundTripped.Status.EndTime != original.Status.EndTime {
+		t.Errorf("endTime after round trip = %q, want %q", roundTripped.Status.EndTime, original.Status.EndTime)
+	}
+	if len(roundTripped.Status.Conditions) != len(original.Status.Conditions) {
+		t.Fatalf("%d conditions after round trip, want %d", len(roundTripped.Status.Conditions), len(original.Status.Conditions))
+	}
+	for i, condition := range roundTripped.Status.Conditions {
+		want := original.Status.Conditions[i]
+		if condition.Type != want.Type || condition.Status != want.Status || condition.Message != want.Message {
+			t.Errorf("condition %d after round trip = %+v, want %+v", i, condition, want)
+		}
+	}
+	if roundTripped.Status.Conditions[2].LastTransitionTime != "2020-01-02 03:05:06 +0000 UTC" {
+		t.Errorf("lastTransitionTime after round trip = %q", roundTripped.Status.Conditions[2].LastTransitionTime)
+	}
+	if roundTripped.Status.Phase != original.Status.Phase || roundTripped.Status.SignedImage != original.Status.SignedImage || roundTripped.Status.UnsignedImage != original.Status.UnsignedImage {
+		t.Errorf("status after round trip = %+v, want %+v", roundTripped.Status, original.Status)
+	}
+}
@@ -0,0 +1,4 @@
+// Package v1alpha2 contains API Schema definitions for the imagesigningrequests v1alpha2 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=imagesigningrequests.cop.redhat.com
+package v1alpha2
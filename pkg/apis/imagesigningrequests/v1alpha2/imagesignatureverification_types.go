@@ -0,0 +1,108 @@
+package v1alpha2
+
+import (
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Outcomes of verifying the signatures of an image
+const (
+	// VerificationPhaseVerified is set when at least one signature of the image was made by a trusted key
+	VerificationPhaseVerified = "Verified"
+	// VerificationPhaseUnverified is set when no signature of the image was made by a trusted key
+	VerificationPhaseUnverified = "Unverified"
+	// VerificationPhaseFailed is set when the signatures of the image could not be checked
+	VerificationPhaseFailed = "Failed"
+)
+
+// ImageSignatureVerificationSpec defines the image to verify and the keys it must be signed with
+// +k8s:openapi-gen=true
+type ImageSignatureVerificationSpec struct {
+	// ContainerImage is the image to verify, referenced in the same way as for an ImageSigningRequest
+	ContainerImage *kapi.ObjectReference `json:"containerImage"`
+	// +optional
+	PullSecret *kapi.LocalObjectReference `json:"pullSecret,omitempty"`
+	// PublicKey holds the ASCII armored public keys trusted to sign the image. Either publicKey or signingKeyRef
+	// must be set.
+	// +optional
+	PublicKey string `json:"publicKey,omitempty"`
+	// SigningKeyRef references a SigningKey in the namespace of the verification whose current and retained keys
+	// are trusted to sign the image
+	// +optional
+	SigningKeyRef *kapi.LocalObjectReference `json:"signingKeyRef,omitempty"`
+}
+
+// ImageSignatureVerificationResult describes a single signature found for the image
+type ImageSignatureVerificationResult struct {
+	// Valid is true when the signature was made by a trusted key over the digest and repository of the image
+	Valid bool `json:"valid"`
+	// KeyID of the key the signature claims to be made by
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+	// Fingerprint of the trusted key that made a valid signature
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Signer is the identity of the trusted key that made a valid signature
+	// +optional
+	Signer string `json:"signer,omitempty"`
+	// DockerReference the image was signed as
+	// +optional
+	DockerReference string `json:"dockerReference,omitempty"`
+	// Message describes why the signature is not valid
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImageSignatureVerificationStatus defines the observed state of ImageSignatureVerification
+// +k8s:openapi-gen=true
+type ImageSignatureVerificationStatus struct {
+	// ObservedGeneration is the most recent generation of the spec that was verified
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is the outcome of the verification, either Verified, Unverified or Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// RequestedImage is the reference of the image to verify, which signatures must name the repository of
+	// +optional
+	RequestedImage string `json:"requestedImage,omitempty"`
+	// ResolvedImage references the manifest digest the requested image was resolved to, which signatures must be
+	// made over
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+	// Signatures lists the result of verifying every signature found for the image
+	// +optional
+	Signatures []ImageSignatureVerificationResult `json:"signatures,omitempty"`
+	// +optional
+	VerificationTime *metav1.Time `json:"verificationTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSignatureVerification is the Schema for the imagesignatureverifications API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=imagesignatureverifications,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".status.resolvedImage"
+// +kubebuilder:printcolumn:name="Verified",type="date",JSONPath=".status.verificationTime"
+type ImageSignatureVerification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageSignatureVerificationSpec   `json:"spec,omitempty"`
+	Status ImageSignatureVerificationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSignatureVerificationList contains a list of ImageSignatureVerification
+type ImageSignatureVerificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageSignatureVerification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageSignatureVerification{}, &ImageSignatureVerificationList{})
+}
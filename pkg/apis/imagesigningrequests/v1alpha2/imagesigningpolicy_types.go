@@ -0,0 +1,97 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageSigningPolicySpec defines which signing keys and images may be used by the namespaces selected by the policy
+// +k8s:openapi-gen=true
+type ImageSigningPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to. An empty selector selects every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// AllowDefaultKey permits requests that do not reference a signing key to be signed with the operator's default key
+	// +optional
+	AllowDefaultKey bool `json:"allowDefaultKey,omitempty"`
+	// AllowedKeys lists the signing key secrets, and the identities within them, the SigningKeys and the keys of key
+	// backends that requests may reference
+	// +optional
+	AllowedKeys []ImageSigningPolicyKey `json:"allowedKeys,omitempty"`
+	// AllowedRepositories lists the repositories that may be signed, such as quay.io/redhat-cop/image-scanning-signing-service.
+	// A trailing * matches any repository with the given prefix. An empty list allows every repository.
+	// +optional
+	AllowedRepositories []string `json:"allowedRepositories,omitempty"`
+}
+
+// ImageSigningPolicyKey identifies a signing key secret or SigningKey within the namespace of a request, or a key
+// of a key backend
+// +k8s:openapi-gen=true
+type ImageSigningPolicyKey struct {
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// SigningKey is the name of a SigningKey. The identity of a SigningKey is fixed, so SignBy does not apply.
+	// +optional
+	SigningKey string `json:"signingKey,omitempty"`
+	// KeyBackend identifies the keys of a key backend that requests may select with keyBackend
+	// +optional
+	KeyBackend *ImageSigningPolicyKeyBackend `json:"keyBackend,omitempty"`
+	// SignBy lists the identities that may be used with the secret or key backend. An empty list allows any identity.
+	// +optional
+	SignBy []string `json:"signBy,omitempty"`
+}
+
+// ImageSigningPolicyKeyBackend identifies keys of HashiCorp Vault or a PKCS#11 token. Empty optional fields match any value.
+// +k8s:openapi-gen=true
+type ImageSigningPolicyKeyBackend struct {
+	// +optional
+	Vault *ImageSigningPolicyVaultKey `json:"vault,omitempty"`
+	// +optional
+	PKCS11 *ImageSigningPolicyPKCS11Key `json:"pkcs11,omitempty"`
+}
+
+// ImageSigningPolicyVaultKey identifies a key of the HashiCorp Vault transit secrets engine
+// +k8s:openapi-gen=true
+type ImageSigningPolicyVaultKey struct {
+	// Address of Vault, as given by requests
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Mount path of the transit secrets engine
+	// +optional
+	Mount string `json:"mount,omitempty"`
+	// KeyName of the transit key
+	KeyName string `json:"keyName"`
+}
+
+// ImageSigningPolicyPKCS11Key identifies a key pair of a PKCS#11 token
+// +k8s:openapi-gen=true
+type ImageSigningPolicyPKCS11Key struct {
+	// TokenLabel of the token holding the key pair
+	TokenLabel string `json:"tokenLabel"`
+	// KeyID is the hex encoded ID of the key pair on the token
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSigningPolicy is the Schema for the imagesigningpolicies API
+// +kubebuilder:resource:path=imagesigningpolicies,scope=Cluster
+type ImageSigningPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageSigningPolicySpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSigningPolicyList contains a list of ImageSigningPolicy
+type ImageSigningPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageSigningPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageSigningPolicy{}, &ImageSigningPolicyList{})
+}
@@ -0,0 +1,4 @@
+package v1alpha2
+
+// Hub marks v1alpha2 as the version every other ImageSigningRequest version converts through
+func (*ImageSigningRequest) Hub() {}
@@ -0,0 +1,273 @@
+package v1alpha2
+
+import (
+	images "github.com/redhat-cop/image-security/pkg/controller/images"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Executors that can sign an image
+const (
+	// ExecutorPod signs in a privileged pod running podman in the target project
+	ExecutorPod = "pod"
+	// ExecutorInProcess signs within the operator, fetching only the manifest of the image
+	ExecutorInProcess = "inProcess"
+)
+
+// Formats a signature can be produced in
+const (
+	// SignatureFormatSimpleSigning is the GPG signed atomic container signature read by podman and CRI-O
+	SignatureFormatSimpleSigning = "simpleSigning"
+	// SignatureFormatCosign is a signature stored as an OCI artifact in the repository of the image as read by cosign
+	SignatureFormatCosign = "cosign"
+)
+
+const (
+	// FailurePolicyFailFast stops signing the images of a request at the first image that cannot be signed
+	FailurePolicyFailFast = "FailFast"
+	// FailurePolicyContinue signs every image of a request that can be signed
+	FailurePolicyContinue = "Continue"
+)
+
+// ImageSigningRequestSpec defines the desired state of ImageSigningRequest
+// +k8s:openapi-gen=true
+type ImageSigningRequestSpec struct {
+	// ContainerImage is the image to sign. Either containerImage or containerImages must be set.
+	// +optional
+	ContainerImage *kapi.ObjectReference `json:"containerImage,omitempty"`
+	// ContainerImages signs many images in a single request, within the operator
+	// +optional
+	ContainerImages []kapi.ObjectReference `json:"containerImages,omitempty"`
+	// FailurePolicy decides whether the images of a request with containerImages that follow a failed image are
+	// still signed, either FailFast or Continue. Defaults to FailFast.
+	// +kubebuilder:validation:Enum=FailFast;Continue
+	// +optional
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+	// +optional
+	PullSecret *kapi.LocalObjectReference `json:"pullSecret,omitempty"`
+	// +optional
+	SigningKeySecretName string `json:"signingKeySecretName,omitempty"`
+	// +optional
+	SigningKeySignBy string `json:"signingKeySignBy,omitempty"`
+	// SigningKeyRef references a SigningKey in the namespace of the request. It takes the place of
+	// SigningKeySecretName and SigningKeySignBy.
+	// +optional
+	SigningKeyRef *kapi.LocalObjectReference `json:"signingKeyRef,omitempty"`
+	// KeyBackend signs with a key held outside of the cluster in place of a key stored in a secret. The secrets the
+	// backend references are read from the namespace of the request.
+	// +optional
+	KeyBackend *KeyBackend `json:"keyBackend,omitempty"`
+	// Executor signs the image, either inProcess or pod. Defaults to the executor configured for the operator.
+	// +kubebuilder:validation:Enum=inProcess;pod
+	// +optional
+	Executor string `json:"executor,omitempty"`
+	// SignatureFormat of the signature, either simpleSigning or cosign. Defaults to simpleSigning. Cosign
+	// signatures are always made in process using the ECDSA or ed25519 key in the cosign.key field of the
+	// signing key secret.
+	// +kubebuilder:validation:Enum=simpleSigning;cosign
+	// +optional
+	SignatureFormat string `json:"signatureFormat,omitempty"`
+	// Platforms limits the platforms of a multi-architecture image that are signed, in the form
+	// os/architecture[/variant]. Every platform is signed when empty. The manifest list itself is always signed.
+	// +optional
+	Platforms []string `json:"platforms,omitempty"`
+	// ActiveDeadlineSeconds is how long the signing pods of the request may take to sign the image, counted from when
+	// the first pod is launched, before the request fails. Defaults to the deadline configured for the operator.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// RetryPolicy relaunches signing pods that fail. Pods are not retried when unset.
+	// +optional
+	RetryPolicy *ImageSigningRetryPolicy `json:"retryPolicy,omitempty"`
+	// Force signs the image even when it already carries a signature made by the key it would be signed with
+	// +optional
+	Force bool `json:"force,omitempty"`
+	// TTLSecondsAfterFinished is how long the request is kept once it has completed or failed before it is deleted.
+	// Defaults to the TTL configured for the operator.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int64 `json:"ttlSecondsAfterFinished,omitempty"`
+	// Build records the OpenShift Build that produced the image, for requests created when a build completes
+	// +optional
+	Build *ImageSigningBuild `json:"build,omitempty"`
+}
+
+// ImageSigningBuild identifies the OpenShift Build that produced the image of a request
+type ImageSigningBuild struct {
+	// Name of the Build
+	Name string `json:"name"`
+	// BuildConfig the Build was started from
+	// +optional
+	BuildConfig string `json:"buildConfig,omitempty"`
+	// Commit is the git commit the image was built from
+	// +optional
+	Commit string `json:"commit,omitempty"`
+}
+
+// ImageSigningRetryPolicy decides how often and when a failed signing pod is relaunched
+type ImageSigningRetryPolicy struct {
+	// MaxAttempts is the number of signing pods launched before the request fails, including the first. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// Backoff is how long to wait after the first failure before relaunching the pod, doubled after every further
+	// failure up to 10 minutes. Defaults to 10s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// ImageSigningAttempt records a signing pod launched for a request
+type ImageSigningAttempt struct {
+	// Pod is the namespace/name of the signing pod
+	Pod string `json:"pod"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// ExitCode of the signing container of a failed pod
+	// +optional
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// Reason the pod failed
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImageSigningFailure describes why a signing pod failed, so that requesters can tell without access to the pod
+type ImageSigningFailure struct {
+	// Pod is the namespace/name of the failed signing pod
+	Pod string `json:"pod"`
+	// ExitCode of the signing container
+	// +optional
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// Reason the signing container or pod stopped, such as Error, OOMKilled or DeadlineExceeded
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message written to the termination log of the signing container, or why it never started
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LogTail holds the last lines of the log of the signing container, with credentials redacted
+	// +optional
+	LogTail string `json:"logTail,omitempty"`
+}
+
+// ImageSigningImageStatus records the signing of one image of a request with containerImages
+type ImageSigningImageStatus struct {
+	// ContainerImage is the image as listed in containerImages
+	ContainerImage kapi.ObjectReference `json:"containerImage"`
+	// +optional
+	RequestedImage string `json:"requestedImage,omitempty"`
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+	// +optional
+	Phase images.ImageExecutionPhase `json:"phase,omitempty"`
+	// Message describes the result of signing the image
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	SignatureArtifact string `json:"signatureArtifact,omitempty"`
+	// +optional
+	Platforms []ImageSigningPlatformStatus `json:"platforms,omitempty"`
+}
+
+// ImageSigningPlatformStatus records the signing of one platform of a multi-architecture image
+type ImageSigningPlatformStatus struct {
+	// Platform in the form os/architecture[/variant]
+	Platform string `json:"platform"`
+	// Digest of the manifest of the platform
+	Digest string `json:"digest"`
+	// Signed is true once a signature of the platform manifest has been stored
+	// +optional
+	Signed bool `json:"signed,omitempty"`
+	// Message describes why the platform could not be signed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImageSigningCondition describes the state of an ImageSigningRequest at a certain point
+// +k8s:openapi-gen=true
+type ImageSigningCondition struct {
+	Type   images.ImageExecutionConditionType `json:"type"`
+	Status kapi.ConditionStatus               `json:"status"`
+	// Reason is a machine readable explanation for the last transition of the condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ImageSigningRequestStatus defines the observed state of ImageSigningRequest
+// +k8s:openapi-gen=true
+type ImageSigningRequestStatus struct {
+	// ObservedGeneration is the most recent generation of the spec acted upon by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions holds the latest observation for each condition type
+	// +optional
+	Conditions []ImageSigningCondition `json:"conditions,omitempty"`
+	// History records condition changes in the order they occurred, oldest entries are dropped first
+	// +optional
+	History []ImageSigningCondition `json:"history,omitempty"`
+	// +optional
+	Phase images.ImageExecutionPhase `json:"phase,omitempty"`
+	// RequestedImage is the reference of the image the request was made for, which signatures are made for
+	// +optional
+	RequestedImage string `json:"requestedImage,omitempty"`
+	// ResolvedImage references the manifest digest the requested image was resolved to, which is what is signed
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+	// +optional
+	SignedImage string `json:"signedImage,omitempty"`
+	// +optional
+	UnsignedImage string `json:"unsignedImage,omitempty"`
+	// SignatureArtifact is the reference of the OCI artifact holding a cosign signature
+	// +optional
+	SignatureArtifact string `json:"signatureArtifact,omitempty"`
+	// Platforms lists the result of signing each platform of a multi-architecture image
+	// +optional
+	Platforms []ImageSigningPlatformStatus `json:"platforms,omitempty"`
+	// Images lists the result of signing each image of a request with containerImages
+	// +optional
+	Images []ImageSigningImageStatus `json:"images,omitempty"`
+	// Attempts lists every signing pod launched for the request, the last being the current attempt
+	// +optional
+	Attempts []ImageSigningAttempt `json:"attempts,omitempty"`
+	// Failure describes the most recent signing pod of the request that failed
+	// +optional
+	Failure *ImageSigningFailure `json:"failure,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSigningRequest is the Schema for the imagesigningrequests API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=imagesigningrequests,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Start",type="date",JSONPath=".status.startTime"
+// +kubebuilder:printcolumn:name="End",type="date",JSONPath=".status.endTime"
+type ImageSigningRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageSigningRequestSpec   `json:"spec,omitempty"`
+	Status ImageSigningRequestStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ImageSigningRequestList contains a list of ImageSigningRequest
+type ImageSigningRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageSigningRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageSigningRequest{}, &ImageSigningRequestList{})
+}
@@ -0,0 +1,136 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SigningKeySpec defines the OpenPGP key the operator generates and rotates
+// +k8s:openapi-gen=true
+type SigningKeySpec struct {
+	// Name of the identity the key is issued to
+	Name string `json:"name"`
+	// Email of the identity the key is issued to
+	Email string `json:"email"`
+	// +optional
+	Comment string `json:"comment,omitempty"`
+	// Algorithm of the generated key. Only RSA is supported.
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+	// KeySize in bits of the generated key. Defaults to 3072.
+	// +optional
+	KeySize int `json:"keySize,omitempty"`
+	// Expiry is how long each generated key remains valid. Keys do not expire when unset.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+	// RotationPeriod is how long a key is used before a new one is generated. Keys are not rotated when unset.
+	// +optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+	// RetainedKeys is the number of previous keys kept for verification after a rotation. Defaults to 3.
+	// +optional
+	RetainedKeys *int `json:"retainedKeys,omitempty"`
+	// KeyBackend holds the key in place of a generated key, which is then neither generated nor rotated. The secrets
+	// the backend references are read from the namespace of the SigningKey.
+	// +optional
+	KeyBackend *KeyBackend `json:"keyBackend,omitempty"`
+}
+
+// KeyBackend holds a signing key outside of the cluster. Images signed with such a key are always signed in process
+// and the private key never leaves the backend. Exactly one backend must be set.
+// +k8s:openapi-gen=true
+type KeyBackend struct {
+	// +optional
+	Vault *VaultTransitKeySource `json:"vault,omitempty"`
+	// +optional
+	PKCS11 *PKCS11KeySource `json:"pkcs11,omitempty"`
+}
+
+// VaultTransitKeySource references an rsa or ecdsa key of the HashiCorp Vault transit secrets engine
+// +k8s:openapi-gen=true
+type VaultTransitKeySource struct {
+	// Address of Vault, such as https://vault.example.com:8200, which must be one of the addresses allowed by the
+	// operator. Defaults to the address of Vault configured for the operator.
+	// +optional
+	Address string `json:"address,omitempty"`
+	// Mount path of the transit secrets engine. Defaults to transit.
+	// +optional
+	Mount string `json:"mount,omitempty"`
+	// KeyName of the transit key
+	KeyName string `json:"keyName"`
+	// TokenSecretName names the secret holding a Vault token allowed to sign with the key in its token field
+	TokenSecretName string `json:"tokenSecretName"`
+}
+
+// PKCS11KeySource references a key pair of a PKCS#11 token, such as a hardware security module, accessed through the
+// PKCS#11 library configured for the operator
+// +k8s:openapi-gen=true
+type PKCS11KeySource struct {
+	// TokenLabel of the token holding the key pair
+	TokenLabel string `json:"tokenLabel"`
+	// KeyID is the hex encoded ID of the key pair on the token
+	KeyID string `json:"keyID"`
+	// PinSecretName names the secret holding the user PIN of the token in its pin field
+	PinSecretName string `json:"pinSecretName"`
+}
+
+// SigningKeyVersion describes a single key generated for a SigningKey
+// +k8s:openapi-gen=true
+type SigningKeyVersion struct {
+	// Fingerprint of the primary key
+	Fingerprint string `json:"fingerprint"`
+	// PublicKey is the ASCII armored public key
+	PublicKey string `json:"publicKey"`
+	// SecretName of the secret in the operator's target project holding the key pair. Keys held by a key backend
+	// have no secret.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// CreationTime of the key
+	CreationTime metav1.Time `json:"creationTime"`
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// SigningKeyStatus defines the observed state of SigningKey
+// +k8s:openapi-gen=true
+type SigningKeyStatus struct {
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CurrentKey is the key used to sign new requests
+	// +optional
+	CurrentKey *SigningKeyVersion `json:"currentKey,omitempty"`
+	// PreviousKeys are retired keys retained for verification, most recent first
+	// +optional
+	PreviousKeys []SigningKeyVersion `json:"previousKeys,omitempty"`
+	// NextRotationTime is when the current key will be replaced
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SigningKey is the Schema for the signingkeys API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=signingkeys,scope=Namespaced
+// +kubebuilder:printcolumn:name="Fingerprint",type="string",JSONPath=".status.currentKey.fingerprint"
+// +kubebuilder:printcolumn:name="Next Rotation",type="date",JSONPath=".status.nextRotationTime"
+type SigningKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SigningKeySpec   `json:"spec,omitempty"`
+	Status SigningKeyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SigningKeyList contains a list of SigningKey
+type SigningKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SigningKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SigningKey{}, &SigningKeyList{})
+}
@@ -0,0 +1,238 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/sirupsen/logrus"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// BuildLabel is set on the ImageSigningRequests created for a Build
+const BuildLabel = "cop.redhat.com/build"
+
+var log = logf.Log.WithName("controller_build")
+
+// Add creates a new Build Controller and adds it to the Manager
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileBuild{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(common.ControllerAgentName)}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("build-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for Builds that have completed
+	err = c.Watch(&source.Kind{Type: &buildv1.Build{}}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isComplete(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isComplete(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isComplete reports whether an object is a Build that has completed
+func isComplete(object runtime.Object) bool {
+	build, ok := object.(*buildv1.Build)
+	return ok && build.Status.Phase == buildv1.BuildPhaseComplete
+}
+
+// blank assignment to verify that ReconcileBuild implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileBuild{}
+
+// ReconcileBuild signs the images produced by the builds of BuildConfigs opted in to automatic signing
+type ReconcileBuild struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile creates an ImageSigningRequest for the output image of a completed Build of a BuildConfig that is opted
+// in to automatic signing. The request signs the exact digest the build pushed and records the build and the git
+// commit it was built from. Requests are named after the Build and owned by it, so they are removed along with it.
+// The request is recorded on the Build, so a request removed once finished is not created again. An existing request
+// of the same name that was not created for the Build is left alone and reported in an event on the Build.
+func (r *ReconcileBuild) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+
+	build := &buildv1.Build{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, build)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if build.Status.Phase != buildv1.BuildPhaseComplete || build.Status.Config == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if _, ok := build.Annotations[common.AutoSignedRequestAnnotation]; ok {
+		return reconcile.Result{}, nil
+	}
+
+	if build.Status.Output.To == nil || build.Status.Output.To.ImageDigest == "" {
+		return reconcile.Result{}, nil
+	}
+
+	buildConfig := &buildv1.BuildConfig{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: build.Status.Config.Name, Namespace: build.Namespace}, buildConfig)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if buildConfig.Annotations[common.AutoSignAnnotation] != "true" {
+		return reconcile.Result{}, nil
+	}
+
+	containerImage, err := outputImage(build)
+	if err != nil {
+		logrus.Warnf("Unable to Sign Output of Build '%s' in Namespace '%s': %v", build.Name, build.Namespace, err)
+		return reconcile.Result{}, nil
+	}
+
+	imageSigningRequest := &v1alpha2.ImageSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      build.Name,
+			Namespace: build.Namespace,
+			Labels: map[string]string{
+				BuildLabel: build.Name,
+			},
+		},
+		Spec: v1alpha2.ImageSigningRequestSpec{
+			ContainerImage: containerImage,
+			Build: &v1alpha2.ImageSigningBuild{
+				Name:        build.Name,
+				BuildConfig: buildConfig.Name,
+			},
+		},
+	}
+
+	if build.Spec.Revision != nil && build.Spec.Revision.Git != nil {
+		imageSigningRequest.Spec.Build.Commit = build.Spec.Revision.Git.Commit
+	}
+
+	if signingKey := buildConfig.Annotations[common.AutoSignKeyAnnotation]; signingKey != "" {
+		imageSigningRequest.Spec.SigningKeyRef = &kapi.LocalObjectReference{Name: signingKey}
+	}
+
+	if build.Spec.Output.PushSecret != nil {
+		imageSigningRequest.Spec.PullSecret = &kapi.LocalObjectReference{Name: build.Spec.Output.PushSecret.Name}
+	}
+
+	if err := controllerutil.SetControllerReference(build, imageSigningRequest, r.scheme); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.client.Create(context.TODO(), imageSigningRequest)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	}
+
+	if err == nil {
+		logrus.Infof("Requested Signing of Image '%s' Built by '%s' in Namespace '%s'", containerImage.Name, build.Name, build.Namespace)
+	} else {
+		existing := &v1alpha2.ImageSigningRequest{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: imageSigningRequest.Name, Namespace: build.Namespace}, existing); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if !isRequestOf(existing, build) {
+			message := fmt.Sprintf("Unable to Sign Output of Build: ImageSigningRequest '%s' Already Exists and Was Not Created for the Build", existing.Name)
+			logrus.Warnf("%s in Namespace '%s'", message, build.Namespace)
+			r.recorder.Event(build, kapi.EventTypeWarning, "SigningRequestConflict", message)
+			return reconcile.Result{}, nil
+		}
+	}
+
+	if build.Annotations == nil {
+		build.Annotations = map[string]string{}
+	}
+	build.Annotations[common.AutoSignedRequestAnnotation] = imageSigningRequest.Name
+
+	return reconcile.Result{}, r.client.Update(context.TODO(), build)
+}
+
+// isRequestOf reports whether an ImageSigningRequest was created for a Build, which labels and owns the request
+func isRequestOf(imageSigningRequest *v1alpha2.ImageSigningRequest, build *buildv1.Build) bool {
+	return imageSigningRequest.Labels[BuildLabel] == build.Name && metav1.IsControlledBy(imageSigningRequest, build)
+}
+
+// outputImage references the exact image a build pushed. Images pushed to an ImageStream of the namespace of the
+// build are referenced through the stream, any other image through its repository.
+func outputImage(build *buildv1.Build) (*kapi.ObjectReference, error) {
+
+	imageDigest := build.Status.Output.To.ImageDigest
+
+	if to := build.Spec.Output.To; to != nil && to.Kind == "ImageStreamTag" && (to.Namespace == "" || to.Namespace == build.Namespace) {
+		return &kapi.ObjectReference{
+			Kind: "ImageStreamImage",
+			Name: fmt.Sprintf("%s@%s", strings.Split(to.Name, ":")[0], imageDigest),
+		}, nil
+	}
+
+	if build.Status.OutputDockerImageReference == "" {
+		return nil, fmt.Errorf("Build has no output image reference")
+	}
+
+	ref, err := registry.ParseReference(build.Status.OutputDockerImageReference)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedDigest, err := digest.Parse(imageDigest)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid output image digest '%s': %v", imageDigest, err)
+	}
+
+	digested, err := reference.WithDigest(reference.TrimNamed(ref), parsedDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kapi.ObjectReference{Kind: "ContainerRepository", Name: digested.String()}, nil
+}
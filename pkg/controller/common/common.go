@@ -3,7 +3,31 @@ package common
 const (
 	ImageSigningTypeAnnotation  = "image-signing"
 	ImageScanningTypeAnnotation = "image-scanning"
+	SigningKeyTypeAnnotation    = "signing-key"
 	ControllerAgentName         = "image-scan-sign-controller"
 	CopOwnerAnnotation          = "cop.redhat.com/owner"
 	CopTypeAnnotation           = "cop.redhat.com/type"
 )
+
+// Keys of the GPG secrets mounted into signing pods
+const (
+	GpgPublicKeyringKey = "pubring.gpg"
+	GpgSecretKeyringKey = "secring.gpg"
+	GpgPublicKeyKey     = "public.asc"
+)
+
+// Annotations opting ImageStreams and BuildConfigs in to automatic signing
+const (
+	// AutoSignAnnotation opts the images of an ImageStream, one of its tags or the builds of a BuildConfig in to
+	// automatic signing
+	AutoSignAnnotation = "cop.redhat.com/auto-sign"
+	// AutoSignKeyAnnotation names the SigningKey that signs images signed automatically. The key configured for the
+	// operator is used when it is not set.
+	AutoSignKeyAnnotation = "cop.redhat.com/auto-sign-signing-key"
+	// AutoSignedImagesAnnotation records the latest image of each tag of an ImageStream a request was created for, so
+	// requests removed once finished are not created again
+	AutoSignedImagesAnnotation = "cop.redhat.com/auto-signed-images"
+	// AutoSignedRequestAnnotation records the request created for the output image of a Build, so a request removed
+	// once finished is not created again
+	AutoSignedRequestAnnotation = "cop.redhat.com/auto-signed-request"
+)
@@ -2,17 +2,81 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	TargetProject        string
-	SigningTemplate      string
-	GpgSecret            string
-	GpgSignBy            string
-	TargetServiceAccount string
-	SignScanImage        string
+	TargetProject         string
+	SigningTemplate       string
+	GpgSecret             string
+	GpgSignBy             string
+	TargetServiceAccount  string
+	SignScanImage         string
+	ScanContentURL        string
+	SigstoreURL           string
+	TrustedKeysConfigMap  string
+	RegistryTLSVerify     bool
+	SigningExecutor       string
+	SigstoreDir           string
+	CosignSecret          string
+	SignatureStorage      string
+	SigstoreServerAddress string
+	KeyBackend            string
+	VaultAddress          string
+	// VaultAllowedAddresses lists the addresses of Vault that key backends may reference besides VaultAddress
+	VaultAllowedAddresses []string
+	VaultTransitMount     string
+	VaultTransitKey       string
+	VaultTokenSecret      string
+	PKCS11Module          string
+	PKCS11TokenLabel      string
+	PKCS11KeyID           string
+	PKCS11PinSecret       string
+	// SigningActiveDeadlineSeconds is how long a request may take to be signed by a signing pod when the request does
+	// not set its own deadline. Requests have no deadline when it is zero.
+	SigningActiveDeadlineSeconds int64
+	// TTLSecondsAfterFinished is how long a request is kept once it has completed or failed when the request does not
+	// set its own TTL. Requests are kept until deleted when it is zero.
+	TTLSecondsAfterFinished int64
+	// KeepFinishedRequests is the most finished requests kept in each namespace, older ones are deleted before their
+	// TTL expires. There is no limit when it is zero.
+	KeepFinishedRequests int64
+	// CleanupInterval is how often finished requests and signing pods are looked for to be deleted
+	CleanupInterval time.Duration
+	// ArchiveFinishedRequests records a summary of every request in a ConfigMap of the target project before the
+	// request is deleted
+	ArchiveFinishedRequests bool
+	// ArchiveTTLSeconds is how long the archive of a request is kept once the request was deleted. Archives are kept
+	// until deleted when it is zero.
+	ArchiveTTLSeconds int64
+	// KeepArchivedRequests is the most archives kept for the requests of each namespace, older ones are deleted before
+	// their TTL expires. There is no limit when it is zero.
+	KeepArchivedRequests int64
 }
 
+const (
+	// SignatureStorageHostPath keeps signatures in the sigstore directory of the node running the signing pod, or of
+	// the operator when signing in process
+	SignatureStorageHostPath = "hostPath"
+	// SignatureStorageImageSignature creates ImageSignatures attached to the Image objects of the cluster
+	SignatureStorageImageSignature = "imageSignature"
+	// SignatureStorageLookaside keeps signatures in the sigstore directory of the operator, which it serves over HTTP
+	SignatureStorageLookaside = "lookaside"
+	// SignatureStorageRegistry pushes signatures through the signature extension API of the registry of the image
+	SignatureStorageRegistry = "registry"
+)
+
+const (
+	// KeyBackendSecret signs with the GPG key pair stored in the secret of the request or GPG_SECRET
+	KeyBackendSecret = "secret"
+	// KeyBackendVault signs with a key of the HashiCorp Vault transit secrets engine
+	KeyBackendVault = "vaultTransit"
+	// KeyBackendPKCS11 signs with a key pair of a PKCS#11 token
+	KeyBackendPKCS11 = "pkcs11"
+)
+
 const (
 	defaultTargetProject        = "image-management"
 	envTargetProject            = "TARGET_PROJECT"
@@ -24,6 +88,52 @@ const (
 	envGpgSignBy                = "GPG_SIGN_BY"
 	defaultSignScanImage        = "image-sign-scan-base"
 	envSignScanImage            = "SIGN_SCAN_IMAGE"
+	defaultScanContentURL       = "https://www.redhat.com/security/data/oval/v2/RHEL8/rhel-8.oval.xml.bz2"
+	envScanContentURL           = "SCAN_CONTENT_URL"
+	defaultSigstoreURL          = ""
+	envSigstoreURL              = "SIGSTORE_URL"
+	defaultTrustedKeysConfigMap = "trusted-keys"
+	envTrustedKeysConfigMap     = "TRUSTED_KEYS_CONFIGMAP"
+	defaultRegistryTLSVerify    = "true"
+	envRegistryTLSVerify        = "REGISTRY_TLS_VERIFY"
+	defaultSigningExecutor      = "pod"
+	envSigningExecutor          = "SIGNING_EXECUTOR"
+	defaultSigstoreDir          = "/var/lib/containers/sigstore"
+	envSigstoreDir              = "SIGSTORE_DIR"
+	defaultCosignSecret         = "cosign"
+	envCosignSecret             = "COSIGN_SECRET"
+	defaultSignatureStorage     = SignatureStorageHostPath
+	envSignatureStorage         = "SIGNATURE_STORAGE"
+	defaultSigstoreServer       = ""
+	envSigstoreServer           = "SIGSTORE_SERVER_ADDRESS"
+	defaultKeyBackend           = KeyBackendSecret
+	envKeyBackend               = "KEY_BACKEND"
+	envVaultAddress             = "VAULT_ADDR"
+	envVaultAllowedAddresses    = "VAULT_ALLOWED_ADDRESSES"
+	defaultVaultTransitMount    = "transit"
+	envVaultTransitMount        = "VAULT_TRANSIT_MOUNT"
+	envVaultTransitKey          = "VAULT_TRANSIT_KEY"
+	defaultVaultTokenSecret     = "vault-token"
+	envVaultTokenSecret         = "VAULT_TOKEN_SECRET"
+	envPKCS11Module             = "PKCS11_MODULE"
+	envPKCS11TokenLabel         = "PKCS11_TOKEN_LABEL"
+	envPKCS11KeyID              = "PKCS11_KEY_ID"
+	defaultPKCS11PinSecret      = "pkcs11-pin"
+	envPKCS11PinSecret          = "PKCS11_PIN_SECRET"
+	defaultActiveDeadline       = "3600"
+	envActiveDeadline           = "SIGNING_ACTIVE_DEADLINE_SECONDS"
+	defaultTTLAfterFinished     = "0"
+	envTTLAfterFinished         = "TTL_SECONDS_AFTER_FINISHED"
+	defaultKeepFinished         = "0"
+	envKeepFinished             = "KEEP_FINISHED_REQUESTS"
+	defaultCleanupInterval      = "5m"
+	envCleanupInterval          = "CLEANUP_INTERVAL"
+	defaultArchiveFinished      = "false"
+	envArchiveFinished          = "ARCHIVE_FINISHED_REQUESTS"
+	defaultArchiveTTL           = "2592000"
+	envArchiveTTL               = "ARCHIVE_TTL_SECONDS"
+	defaultKeepArchived         = "0"
+	envKeepArchived             = "KEEP_ARCHIVED_REQUESTS"
 )
 
 func LoadConfig() Config {
@@ -40,6 +150,66 @@ func LoadConfig() Config {
 
 	config.SignScanImage = getProperty(envSignScanImage, defaultSignScanImage)
 
+	config.ScanContentURL = getProperty(envScanContentURL, defaultScanContentURL)
+
+	config.SigstoreURL = getProperty(envSigstoreURL, defaultSigstoreURL)
+
+	config.TrustedKeysConfigMap = getProperty(envTrustedKeysConfigMap, defaultTrustedKeysConfigMap)
+
+	config.RegistryTLSVerify = getProperty(envRegistryTLSVerify, defaultRegistryTLSVerify) != "false"
+
+	config.SigningExecutor = getProperty(envSigningExecutor, defaultSigningExecutor)
+
+	config.SigstoreDir = getProperty(envSigstoreDir, defaultSigstoreDir)
+
+	config.CosignSecret = getProperty(envCosignSecret, defaultCosignSecret)
+
+	config.SignatureStorage = getProperty(envSignatureStorage, defaultSignatureStorage)
+
+	config.SigstoreServerAddress = getProperty(envSigstoreServer, defaultSigstoreServer)
+
+	config.KeyBackend = getProperty(envKeyBackend, defaultKeyBackend)
+
+	config.VaultAddress = getProperty(envVaultAddress, "")
+
+	for _, address := range strings.Split(getProperty(envVaultAllowedAddresses, ""), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			config.VaultAllowedAddresses = append(config.VaultAllowedAddresses, address)
+		}
+	}
+
+	config.VaultTransitMount = getProperty(envVaultTransitMount, defaultVaultTransitMount)
+
+	config.VaultTransitKey = getProperty(envVaultTransitKey, "")
+
+	config.VaultTokenSecret = getProperty(envVaultTokenSecret, defaultVaultTokenSecret)
+
+	config.PKCS11Module = getProperty(envPKCS11Module, "")
+
+	config.PKCS11TokenLabel = getProperty(envPKCS11TokenLabel, "")
+
+	config.PKCS11KeyID = getProperty(envPKCS11KeyID, "")
+
+	config.PKCS11PinSecret = getProperty(envPKCS11PinSecret, defaultPKCS11PinSecret)
+
+	config.SigningActiveDeadlineSeconds = getIntProperty(envActiveDeadline, defaultActiveDeadline)
+
+	config.TTLSecondsAfterFinished = getIntProperty(envTTLAfterFinished, defaultTTLAfterFinished)
+
+	config.KeepFinishedRequests = getIntProperty(envKeepFinished, defaultKeepFinished)
+
+	cleanupInterval, err := time.ParseDuration(getProperty(envCleanupInterval, defaultCleanupInterval))
+	if err != nil || cleanupInterval <= 0 {
+		cleanupInterval, _ = time.ParseDuration(defaultCleanupInterval)
+	}
+	config.CleanupInterval = cleanupInterval
+
+	config.ArchiveFinishedRequests = getProperty(envArchiveFinished, defaultArchiveFinished) == "true"
+
+	config.ArchiveTTLSeconds = getIntProperty(envArchiveTTL, defaultArchiveTTL)
+
+	config.KeepArchivedRequests = getIntProperty(envKeepArchived, defaultKeepArchived)
+
 	return config
 
 }
@@ -53,3 +223,13 @@ func getProperty(envProp string, defaultValue string) string {
 
 	return value
 }
+
+// getIntProperty returns an integer property, falling back to the default when the value is not a number
+func getIntProperty(envProp string, defaultValue string) int64 {
+	value, err := strconv.ParseInt(getProperty(envProp, defaultValue), 10, 64)
+	if err != nil {
+		value, _ = strconv.ParseInt(defaultValue, 10, 64)
+	}
+
+	return value
+}
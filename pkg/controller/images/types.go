@@ -24,5 +24,23 @@ type ImageExecutionConditionType string
 const (
 	ImageExecutionConditionInitialization = "Initialization"
 	ImageExecutionConditionSigning        = "Signing"
+	ImageExecutionConditionScanning       = "Scanning"
 	ImageExecutionConditionFinished       = "Finished"
+	ImageExecutionConditionReady          = "Ready"
+)
+
+// Reasons recorded on the conditions of an ImageSigningRequest
+const (
+	ReasonSigningPodLaunched   = "SigningPodLaunched"
+	ReasonSigningInProcess     = "SigningInProcess"
+	ReasonInitializationFailed = "InitializationFailed"
+	ReasonInvalidRequest       = "InvalidRequest"
+	ReasonPolicyDenied         = "PolicyDenied"
+	ReasonSigningFailed        = "SigningFailed"
+	ReasonSigned               = "Signed"
+	ReasonAlreadySigned        = "AlreadySigned"
+	ReasonSigningInProgress    = "SigningInProgress"
+	ReasonSigningRetrying      = "SigningRetrying"
+	ReasonDeadlineExceeded     = "DeadlineExceeded"
+	ReasonSigningNotStarted    = "SigningNotStarted"
 )
@@ -0,0 +1,136 @@
+package imagescanningrequest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	imagescanningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagescanningrequests/v1alpha1"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/imagescanningrequest/scanning"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+)
+
+var log = logf.Log.WithName("controller_imagescanningrequest")
+
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	configuration := config.LoadConfig()
+	client, err := imageset.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil
+	}
+	return &ReconcileImageScanningRequest{client: mgr.GetClient(), scheme: mgr.GetScheme(), config: configuration, imageClient: client}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("imagescanningrequest-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ImageScanningRequest
+	err = c.Watch(&source.Kind{Type: &imagescanningrequestsv1alpha1.ImageScanningRequest{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileImageScanningRequest implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileImageScanningRequest{}
+
+// ReconcileImageScanningRequest reconciles a ImageScanningRequest object
+type ReconcileImageScanningRequest struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client      client.Client
+	scheme      *runtime.Scheme
+	config      config.Config
+	imageClient *imageset.ImageV1Client
+}
+
+// Reconcile resolves the image referenced by a new ImageScanningRequest and launches a scanning pod in the
+// target project. The pod controller is responsible for moving the request to its final phase.
+func (r *ReconcileImageScanningRequest) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ImageScanningRequest")
+
+	// Fetch the ImageScanningRequest instance
+	instance := &imagescanningrequestsv1alpha1.ImageScanningRequest{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	imageScanningRequestMetadataKey, _ := cache.MetaNamespaceKeyFunc(instance)
+	emptyPhase := imagescanningrequestsv1alpha1.ImageScanningRequestStatus{}.Phase
+	if instance.Status.Phase == emptyPhase {
+
+		instance.Status.EndTime = time.Time{}.String() // Need an initial value since time is not nullable
+		instance.Status.StartTime = time.Time{}.String()
+
+		// Retrieve pull secret if available
+		pullSecret := ""
+		if instance.Spec.PullSecret != nil {
+			pullSecret = instance.Spec.PullSecret.Name
+		}
+
+		location, err := signing.GetImageLocationFromRequest(r.client, r.imageClient, r.config, instance.Spec.ContainerImage, instance.ObjectMeta.Namespace, pullSecret)
+
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		scanningPodName, err := scanning.LaunchScanningPod(r.client, r.config, instance, location.Resolved, location.Digest, string(instance.ObjectMeta.UID), imageScanningRequestMetadataKey, pullSecret)
+
+		if err != nil {
+			errorMessage := fmt.Sprintf("Error Occurred Creating Scanning Pod '%v'", err)
+
+			logrus.Errorf(errorMessage)
+
+			err = scanning.UpdateOnImageScanningInitializationFailure(r.client, errorMessage, *instance)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		logrus.Infof("Scanning Pod Launched '%s'", scanningPodName)
+
+		err = scanning.UpdateOnScanningPodLaunch(r.client, fmt.Sprintf("Scanning Pod Launched '%s'", scanningPodName), *instance)
+
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+	}
+
+	return reconcile.Result{}, nil
+}
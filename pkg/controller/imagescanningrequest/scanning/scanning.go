@@ -0,0 +1,157 @@
+package scanning
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagescanningrequests/v1alpha1"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/images"
+	"github.com/redhat-cop/image-security/pkg/controller/util"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func UpdateOnImageScanningCompletionError(client client.Client, message string, imageScanningRequest v1alpha1.ImageScanningRequest) error {
+
+	condition := util.NewImageExecutionCondition(message, corev1.ConditionFalse, images.ImageExecutionConditionFinished)
+
+	imageScanningRequest.Status.EndTime = condition.LastTransitionTime
+
+	return updateImageScanningRequest(client, &imageScanningRequest, condition, images.PhaseFailed)
+}
+
+func UpdateOnImageScanningCompletionSuccess(client client.Client, message string, scannedImage string, results *v1alpha1.ImageScanningResults, imageScanningRequest v1alpha1.ImageScanningRequest) error {
+
+	condition := util.NewImageExecutionCondition(message, corev1.ConditionTrue, images.ImageExecutionConditionFinished)
+
+	imageScanningRequest.Status.ScannedImage = scannedImage
+	imageScanningRequest.Status.Results = results
+	imageScanningRequest.Status.EndTime = condition.LastTransitionTime
+
+	return updateImageScanningRequest(client, &imageScanningRequest, condition, images.PhaseCompleted)
+}
+
+func UpdateOnImageScanningInitializationFailure(client client.Client, message string, imageScanningRequest v1alpha1.ImageScanningRequest) error {
+
+	condition := util.NewImageExecutionCondition(message, corev1.ConditionFalse, images.ImageExecutionConditionInitialization)
+
+	imageScanningRequest.Status.StartTime = condition.LastTransitionTime
+	imageScanningRequest.Status.EndTime = condition.LastTransitionTime
+
+	return updateImageScanningRequest(client, &imageScanningRequest, condition, images.PhaseFailed)
+}
+
+func UpdateOnScanningPodLaunch(client client.Client, message string, imageScanningRequest v1alpha1.ImageScanningRequest) error {
+
+	condition := util.NewImageExecutionCondition(message, corev1.ConditionTrue, images.ImageExecutionConditionInitialization)
+
+	imageScanningRequest.Status.StartTime = condition.LastTransitionTime
+	imageScanningRequest.Status.EndTime = metav1.NewTime(time.Time{}).String()
+
+	return updateImageScanningRequest(client, &imageScanningRequest, condition, images.PhaseRunning)
+}
+
+func updateImageScanningRequest(client client.Client, imageScanningRequest *v1alpha1.ImageScanningRequest, condition images.ImageExecutionCondition, phase images.ImageExecutionPhase) error {
+
+	imageScanningRequest.Status.Conditions = append(imageScanningRequest.Status.Conditions, condition)
+	imageScanningRequest.Status.Phase = phase
+
+	err := client.Status().Update(context.TODO(), imageScanningRequest)
+	return err
+}
+
+func LaunchScanningPod(client client.Client, config config.Config, instance *v1alpha1.ImageScanningRequest, image string, imageDigest string, ownerID string, ownerReference string, pullSecret string) (string, error) {
+
+	pod := createScanningPod(instance, config.SignScanImage, config.TargetProject, config.ScanContentURL, image, imageDigest, ownerID, ownerReference, config.TargetServiceAccount, pullSecret)
+
+	err := client.Create(context.TODO(), pod)
+
+	if err != nil {
+		logrus.Errorf("Error Creating Pod: %v'", err)
+		return "", err
+	}
+
+	var key string
+	if key, err = cache.MetaNamespaceKeyFunc(pod); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func createScanningPod(instance *v1alpha1.ImageScanningRequest, signScanImage string, targetProject string, scanContentURL string, image string, imageDigest string, ownerID string, ownerReference string, serviceAccount string, pullSecret string) *corev1.Pod {
+	priv := true
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ownerID,
+			Namespace:   targetProject,
+			Labels:      map[string]string{"type": common.ImageScanningTypeAnnotation},
+			Annotations: map[string]string{common.CopOwnerAnnotation: ownerReference, common.CopTypeAnnotation: common.ImageScanningTypeAnnotation},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "image-scanner",
+				Image:           signScanImage,
+				ImagePullPolicy: corev1.PullAlways,
+				Command:         []string{"/bin/bash", "-c", "/usr/local/bin/scan-image"},
+				Env: []corev1.EnvVar{
+					{
+						Name:      "NAMESPACE",
+						ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}},
+					},
+					{
+						Name:  "IMAGE",
+						Value: image,
+					},
+					{
+						Name:  "DIGEST",
+						Value: imageDigest,
+					},
+					{
+						Name:  "SCAN_CONTENT_URL",
+						Value: scanContentURL,
+					},
+					{
+						Name:  "SECRET",
+						Value: pullSecret,
+					},
+					{
+						Name:  "SECRET_NAMESPACE",
+						Value: instance.ObjectMeta.Namespace,
+					},
+				},
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: &priv,
+				},
+				TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+			}},
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: serviceAccount,
+		},
+	}
+
+	return pod
+}
+
+// ParseScanningResults reads the summary written by scan-image to the termination log
+func ParseScanningResults(terminationMessage string) (*v1alpha1.ImageScanningResults, error) {
+	results := &v1alpha1.ImageScanningResults{}
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(terminationMessage)), results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
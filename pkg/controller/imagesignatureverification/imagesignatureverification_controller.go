@@ -0,0 +1,222 @@
+package imagesignatureverification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	digest "github.com/opencontainers/go-digest"
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_imagesignatureverification")
+
+// Add creates a new ImageSignatureVerification Controller and adds it to the Manager
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	client, err := imageset.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil
+	}
+	return &ReconcileImageSignatureVerification{client: mgr.GetClient(), scheme: mgr.GetScheme(), config: config.LoadConfig(), imageClient: client}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("imagesignatureverification-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ImageSignatureVerification
+	err = c.Watch(&source.Kind{Type: &v1alpha2.ImageSignatureVerification{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileImageSignatureVerification implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileImageSignatureVerification{}
+
+// ReconcileImageSignatureVerification reconciles an ImageSignatureVerification object
+type ReconcileImageSignatureVerification struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	config      config.Config
+	imageClient *imageset.ImageV1Client
+}
+
+// Reconcile verifies every signature stored for the image of an ImageSignatureVerification against the keys it
+// trusts. Each generation of the spec is verified once, so changing the spec verifies the image again.
+func (r *ReconcileImageSignatureVerification) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ImageSignatureVerification")
+
+	instance := &v1alpha2.ImageSignatureVerification{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if instance.DeletionTimestamp != nil || (instance.Status.Phase != "" && instance.Status.ObservedGeneration == instance.Generation) {
+		return reconcile.Result{}, nil
+	}
+
+	instance.Status = v1alpha2.ImageSignatureVerificationStatus{}
+
+	if err := r.verify(instance); err != nil {
+		logrus.Warnf("Error Verifying Signatures of ImageSignatureVerification '%s/%s': %v", instance.Namespace, instance.Name, err)
+
+		instance.Status.Phase = v1alpha2.VerificationPhaseFailed
+		instance.Status.Message = err.Error()
+	}
+
+	now := metav1.Now()
+	instance.Status.VerificationTime = &now
+	instance.Status.ObservedGeneration = instance.Generation
+
+	return reconcile.Result{}, r.client.Status().Update(context.TODO(), instance)
+}
+
+// verify resolves the image to its manifest digest and records the result of verifying each of its signatures in the
+// status. An error is returned when the signatures could not be checked at all.
+func (r *ReconcileImageSignatureVerification) verify(instance *v1alpha2.ImageSignatureVerification) error {
+
+	keyring, err := r.keyring(instance)
+	if err != nil {
+		return err
+	}
+
+	pullSecret := ""
+	if instance.Spec.PullSecret != nil {
+		pullSecret = instance.Spec.PullSecret.Name
+	}
+
+	location, err := signing.GetImageLocationFromRequest(r.client, r.imageClient, r.config, instance.Spec.ContainerImage, instance.Namespace, pullSecret)
+	if err != nil {
+		return err
+	}
+
+	instance.Status.RequestedImage = location.Requested
+	instance.Status.ResolvedImage = location.Resolved
+
+	ref, err := registry.ParseReference(location.Requested)
+	if err != nil {
+		return err
+	}
+
+	manifestDigest, err := digest.Parse(location.Digest)
+	if err != nil {
+		return fmt.Errorf("Invalid digest '%s': %v", location.Digest, err)
+	}
+
+	store, err := signing.SignatureSource(r.client, r.imageClient, r.config, instance.Namespace, pullSecret)
+	if err != nil {
+		return err
+	}
+
+	signatures, err := store.Signatures(ref, manifestDigest)
+	if err != nil {
+		return fmt.Errorf("Error reading signatures: %v", err)
+	}
+
+	valid := 0
+	for _, candidate := range signatures {
+		result := v1alpha2.ImageSignatureVerificationResult{KeyID: signature.IssuerKeyID(candidate)}
+
+		verified, err := signature.Verify(candidate, keyring, ref, manifestDigest)
+		if err != nil {
+			result.Message = err.Error()
+		} else {
+			result.Valid = true
+			result.Fingerprint = verified.Fingerprint
+			result.Signer = verified.Signer
+			result.DockerReference = verified.DockerReference
+			valid++
+		}
+
+		instance.Status.Signatures = append(instance.Status.Signatures, result)
+	}
+
+	switch {
+	case len(signatures) == 0:
+		instance.Status.Phase = v1alpha2.VerificationPhaseUnverified
+		instance.Status.Message = fmt.Sprintf("No Signatures Found for Digest '%s'", manifestDigest)
+	case valid == 0:
+		instance.Status.Phase = v1alpha2.VerificationPhaseUnverified
+		instance.Status.Message = fmt.Sprintf("None of %d Signatures Were Made by a Trusted Key", len(signatures))
+	default:
+		instance.Status.Phase = v1alpha2.VerificationPhaseVerified
+		instance.Status.Message = fmt.Sprintf("%d of %d Signatures Verified", valid, len(signatures))
+	}
+
+	logrus.Infof("Image '%s' of ImageSignatureVerification '%s/%s' is %s: %s", location.Resolved, instance.Namespace, instance.Name, instance.Status.Phase, instance.Status.Message)
+
+	return nil
+}
+
+// keyring returns the public keys a verification trusts, either given in its spec or published by a SigningKey
+func (r *ReconcileImageSignatureVerification) keyring(instance *v1alpha2.ImageSignatureVerification) (openpgp.EntityList, error) {
+
+	if (instance.Spec.PublicKey == "") == (instance.Spec.SigningKeyRef == nil) {
+		return nil, errors.New("Exactly one of publicKey and signingKeyRef must be set")
+	}
+
+	keys := [][]byte{[]byte(instance.Spec.PublicKey)}
+
+	if instance.Spec.SigningKeyRef != nil {
+		signingKey := &v1alpha2.SigningKey{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.SigningKeyRef.Name, Namespace: instance.Namespace}, signingKey); err != nil {
+			return nil, fmt.Errorf("Error retrieving SigningKey '%s': %v", instance.Spec.SigningKeyRef.Name, err)
+		}
+
+		if signingKey.Status.CurrentKey == nil {
+			return nil, fmt.Errorf("SigningKey '%s' has no key yet", signingKey.Name)
+		}
+
+		keys = append(keys, []byte(signingKey.Status.CurrentKey.PublicKey))
+		for _, previous := range signingKey.Status.PreviousKeys {
+			keys = append(keys, []byte(previous.PublicKey))
+		}
+	}
+
+	keyring, err := signature.Keyring(keys...)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid public key: %v", err)
+	}
+
+	if len(keyring) == 0 {
+		return nil, errors.New("No public keys found to verify with")
+	}
+
+	return keyring, nil
+}
@@ -0,0 +1,358 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/images"
+	"github.com/redhat-cop/image-security/pkg/controller/util"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Archived summaries of deleted requests
+const (
+	archivePrefix         = "image-signing-archive"
+	archiveTypeAnnotation = "image-signing-archive"
+	archiveSummaryKey     = "summary.json"
+)
+
+// Add runs the cleaner of finished ImageSigningRequests with the Manager
+func Add(mgr manager.Manager) error {
+	return mgr.Add(NewCleaner(mgr.GetClient(), config.LoadConfig()))
+}
+
+// Cleaner deletes finished ImageSigningRequests once their TTL has expired or their namespace holds more finished
+// requests than are kept, along with the terminal signing pods in the target project no request needs any longer and
+// the archives of deleted requests that are no longer kept
+type Cleaner struct {
+	client client.Client
+	config config.Config
+}
+
+var _ manager.Runnable = &Cleaner{}
+
+// NewCleaner returns a cleaner for the requests the client can read
+func NewCleaner(c client.Client, configuration config.Config) *Cleaner {
+	return &Cleaner{client: c, config: configuration}
+}
+
+// Start cleans up every cleanup interval until the stop channel is closed
+func (c *Cleaner) Start(stop <-chan struct{}) error {
+	logrus.Infof("Cleaning Up Finished ImageSigningRequests Every %s", c.config.CleanupInterval)
+
+	wait.Until(func() {
+		if err := c.Clean(time.Now()); err != nil {
+			logrus.Errorf("Error Cleaning Up Finished ImageSigningRequests: %v", err)
+		}
+	}, c.config.CleanupInterval, stop)
+
+	return nil
+}
+
+// Clean deletes the requests that have expired at now, followed by the signing pods and archives that are no longer
+// needed
+func (c *Cleaner) Clean(now time.Time) error {
+
+	requests := &v1alpha2.ImageSigningRequestList{}
+	if err := c.client.List(context.TODO(), requests); err != nil {
+		return err
+	}
+
+	finished := map[string][]*v1alpha2.ImageSigningRequest{}
+	for i := range requests.Items {
+		instance := &requests.Items[i]
+		if instance.DeletionTimestamp == nil && finishedAt(instance) != nil {
+			finished[instance.Namespace] = append(finished[instance.Namespace], instance)
+		}
+	}
+
+	for _, namespaceRequests := range finished {
+		// Newest first, so that the requests beyond the number kept are the oldest
+		sort.SliceStable(namespaceRequests, func(i, j int) bool {
+			return finishedAt(namespaceRequests[j]).Before(finishedAt(namespaceRequests[i]))
+		})
+
+		for i, instance := range namespaceRequests {
+			reason := ""
+
+			if ttl, ok := ttlSecondsAfterFinished(instance, c.config); ok && !now.Before(finishedAt(instance).Add(time.Duration(ttl)*time.Second)) {
+				reason = fmt.Sprintf("TTL of %d Seconds After Finishing Expired", ttl)
+			} else if c.config.KeepFinishedRequests > 0 && int64(i) >= c.config.KeepFinishedRequests {
+				reason = fmt.Sprintf("Only the Last %d Finished Requests of the Namespace Are Kept", c.config.KeepFinishedRequests)
+			}
+
+			if reason == "" {
+				continue
+			}
+
+			// A request that cannot be deleted is retried on the next pass without holding up the others
+			if err := c.deleteRequest(instance, reason, now); err != nil {
+				logrus.Errorf("Error Deleting ImageSigningRequest '%s/%s': %v", instance.Namespace, instance.Name, err)
+			}
+		}
+	}
+
+	if err := c.cleanPods(); err != nil {
+		return err
+	}
+
+	return c.cleanArchives(now)
+}
+
+// deleteRequest archives a summary of a request when enabled and deletes it. The finalizer of the request removes
+// its signing pods and key secret copy.
+func (c *Cleaner) deleteRequest(instance *v1alpha2.ImageSigningRequest, reason string, now time.Time) error {
+
+	if c.config.ArchiveFinishedRequests {
+		if err := c.archive(instance, reason, now); err != nil {
+			return fmt.Errorf("Error Archiving ImageSigningRequest '%s/%s': %v", instance.Namespace, instance.Name, err)
+		}
+	}
+
+	if err := c.client.Delete(context.TODO(), instance); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	logrus.Infof("Deleted ImageSigningRequest '%s/%s': %s", instance.Namespace, instance.Name, reason)
+
+	return nil
+}
+
+// summary is the record of a deleted request kept in its archive
+type summary struct {
+	Namespace         string       `json:"namespace"`
+	Name              string       `json:"name"`
+	UID               types.UID    `json:"uid"`
+	Phase             string       `json:"phase"`
+	Reason            string       `json:"reason,omitempty"`
+	Message           string       `json:"message,omitempty"`
+	RequestedImage    string       `json:"requestedImage,omitempty"`
+	ResolvedImage     string       `json:"resolvedImage,omitempty"`
+	SignedImage       string       `json:"signedImage,omitempty"`
+	SignatureArtifact string       `json:"signatureArtifact,omitempty"`
+	Attempts          int          `json:"attempts,omitempty"`
+	StartTime         *metav1.Time `json:"startTime,omitempty"`
+	EndTime           *metav1.Time `json:"endTime,omitempty"`
+	DeletionTime      metav1.Time  `json:"deletionTime"`
+	DeletionReason    string       `json:"deletionReason"`
+}
+
+// archive records a summary of a request in a ConfigMap of the target project, which outlives the request
+func (c *Cleaner) archive(instance *v1alpha2.ImageSigningRequest, reason string, now time.Time) error {
+
+	record := summary{
+		Namespace:         instance.Namespace,
+		Name:              instance.Name,
+		UID:               instance.UID,
+		Phase:             string(instance.Status.Phase),
+		RequestedImage:    instance.Status.RequestedImage,
+		ResolvedImage:     instance.Status.ResolvedImage,
+		SignedImage:       instance.Status.SignedImage,
+		SignatureArtifact: instance.Status.SignatureArtifact,
+		Attempts:          len(instance.Status.Attempts),
+		StartTime:         instance.Status.StartTime,
+		EndTime:           instance.Status.EndTime,
+		DeletionTime:      metav1.NewTime(now),
+		DeletionReason:    reason,
+	}
+
+	if ready := util.FindImageSigningCondition(&instance.Status, images.ImageExecutionConditionReady); ready != nil {
+		record.Reason = ready.Reason
+		record.Message = ready.Message
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%s", archivePrefix, instance.UID),
+			Namespace:   c.config.TargetProject,
+			Labels:      map[string]string{"type": archiveTypeAnnotation},
+			Annotations: map[string]string{common.CopOwnerAnnotation: fmt.Sprintf("%s/%s", instance.Namespace, instance.Name), common.CopTypeAnnotation: archiveTypeAnnotation},
+		},
+		Data: map[string]string{archiveSummaryKey: string(data)},
+	}
+
+	// The archive of a request whose deletion failed before is kept
+	if err := c.client.Create(context.TODO(), configMap); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// cleanArchives deletes the archives of deleted requests once their TTL has expired or more archives are kept for the
+// requests of a namespace than allowed, oldest first
+func (c *Cleaner) cleanArchives(now time.Time) error {
+
+	if c.config.ArchiveTTLSeconds <= 0 && c.config.KeepArchivedRequests <= 0 {
+		return nil
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.client.List(context.TODO(), configMaps, client.InNamespace(c.config.TargetProject), client.MatchingLabels{"type": archiveTypeAnnotation}); err != nil {
+		return err
+	}
+
+	archives := map[string][]*corev1.ConfigMap{}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if configMap.DeletionTimestamp == nil {
+			namespace := archivedNamespace(configMap)
+			archives[namespace] = append(archives[namespace], configMap)
+		}
+	}
+
+	for _, namespaceArchives := range archives {
+		// Newest first, so that the archives beyond the number kept are the oldest
+		sort.SliceStable(namespaceArchives, func(i, j int) bool {
+			return archivedAt(namespaceArchives[j]).Before(archivedAt(namespaceArchives[i]))
+		})
+
+		for i, configMap := range namespaceArchives {
+			expired := c.config.ArchiveTTLSeconds > 0 && !now.Before(archivedAt(configMap).Add(time.Duration(c.config.ArchiveTTLSeconds)*time.Second))
+			beyondKept := c.config.KeepArchivedRequests > 0 && int64(i) >= c.config.KeepArchivedRequests
+
+			if !expired && !beyondKept {
+				continue
+			}
+
+			if err := c.client.Delete(context.TODO(), configMap); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+
+			logrus.Infof("Deleted Archive '%s/%s' of ImageSigningRequest '%s'", configMap.Namespace, configMap.Name, configMap.Annotations[common.CopOwnerAnnotation])
+		}
+	}
+
+	return nil
+}
+
+// archivedNamespace returns the namespace of the request an archive was recorded for
+func archivedNamespace(configMap *corev1.ConfigMap) string {
+
+	namespace, _, err := cache.SplitMetaNamespaceKey(configMap.Annotations[common.CopOwnerAnnotation])
+	if err != nil {
+		return ""
+	}
+
+	return namespace
+}
+
+// archivedAt returns when the request of an archive was deleted, or when the archive was created if its summary
+// cannot be read
+func archivedAt(configMap *corev1.ConfigMap) time.Time {
+
+	record := summary{}
+	if err := json.Unmarshal([]byte(configMap.Data[archiveSummaryKey]), &record); err != nil || record.DeletionTime.IsZero() {
+		return configMap.CreationTimestamp.Time
+	}
+
+	return record.DeletionTime.Time
+}
+
+// cleanPods deletes the terminal signing pods in the target project whose request has finished or no longer exists.
+// The last failed pod of a running request is kept, as relaunching it reads its spec.
+func (c *Cleaner) cleanPods() error {
+
+	pods := &corev1.PodList{}
+	if err := c.client.List(context.TODO(), pods, client.InNamespace(c.config.TargetProject), client.MatchingLabels{"type": common.ImageSigningTypeAnnotation}); err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+
+		needed, err := c.podNeeded(pod)
+		if err != nil {
+			return err
+		}
+
+		if needed {
+			continue
+		}
+
+		if err := c.client.Delete(context.TODO(), pod); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		logrus.Infof("Deleted Signing Pod '%s/%s'", pod.Namespace, pod.Name)
+	}
+
+	return nil
+}
+
+// podNeeded returns whether the request that launched a terminal signing pod is still running
+func (c *Cleaner) podNeeded(pod *corev1.Pod) (bool, error) {
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(pod.Annotations[common.CopOwnerAnnotation])
+	if err != nil || name == "" {
+		return false, nil
+	}
+
+	instance := &v1alpha2.ImageSigningRequest{}
+	err = c.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, instance)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Pods are named after the request that launched them, a request recreated with the same name does not own them
+	if !strings.HasPrefix(pod.Name, string(instance.UID)) {
+		return false, nil
+	}
+
+	return finishedAt(instance) == nil, nil
+}
+
+// finishedAt returns when a request completed or failed, or nil while it has not finished
+func finishedAt(instance *v1alpha2.ImageSigningRequest) *metav1.Time {
+
+	if instance.Status.Phase != images.PhaseCompleted && instance.Status.Phase != images.PhaseFailed {
+		return nil
+	}
+
+	if instance.Status.EndTime != nil {
+		return instance.Status.EndTime
+	}
+
+	if ready := util.FindImageSigningCondition(&instance.Status, images.ImageExecutionConditionReady); ready != nil {
+		return &ready.LastTransitionTime
+	}
+
+	return &instance.CreationTimestamp
+}
+
+// ttlSecondsAfterFinished returns how long a finished request is kept, and whether it expires at all
+func ttlSecondsAfterFinished(instance *v1alpha2.ImageSigningRequest, configuration config.Config) (int64, bool) {
+
+	if instance.Spec.TTLSecondsAfterFinished != nil {
+		return *instance.Spec.TTLSecondsAfterFinished, true
+	}
+
+	return configuration.TTLSecondsAfterFinished, configuration.TTLSecondsAfterFinished > 0
+}
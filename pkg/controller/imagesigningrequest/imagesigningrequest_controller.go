@@ -3,15 +3,20 @@ package imagesigningrequest
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
-	imagesigningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha1"
+	imagesigningrequestsv1alpha2 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
 	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/images"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/policy"
 	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	"github.com/redhat-cop/image-security/pkg/registry"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -28,6 +33,13 @@ import (
 
 var log = logf.Log.WithName("controller_imagesigningrequest")
 
+// imageSigningRequestFinalizer allows the key secret copy and signing pods in the target project to be removed along
+// with the ImageSigningRequest
+const imageSigningRequestFinalizer = "imagesigningrequests.cop.redhat.com/imagesigningrequest"
+
+// signingKeyRetryInterval is how long a request waits before checking again whether its SigningKey has generated a key
+const signingKeyRetryInterval = 10 * time.Second
+
 func Add(mgr manager.Manager) error {
 	return add(mgr, newReconciler(mgr))
 }
@@ -51,14 +63,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to ImageSigningRequest
-	err = c.Watch(&source.Kind{Type: &imagesigningrequestsv1alpha1.ImageSigningRequest{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &imagesigningrequestsv1alpha2.ImageSigningRequest{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
 		return err
 	}
 
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &imagesigningrequestsv1alpha1.ImageSigningRequest{},
+		OwnerType:    &imagesigningrequestsv1alpha2.ImageSigningRequest{},
 	})
 	if err != nil {
 		return err
@@ -92,7 +104,7 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 	reqLogger.Info("Reconciling ImageSigningRequest")
 
 	// Fetch the ImageSigningRequest instance
-	instance := &imagesigningrequestsv1alpha1.ImageSigningRequest{}
+	instance := &imagesigningrequestsv1alpha2.ImageSigningRequest{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -102,30 +114,294 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 		return reconcile.Result{}, err
 	}
 
+	if instance.DeletionTimestamp != nil {
+		if !hasFinalizer(instance) {
+			return reconcile.Result{}, nil
+		}
+
+		if err := r.cleanUp(instance); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		removeFinalizer(instance)
+		return reconcile.Result{}, r.client.Update(context.TODO(), instance)
+	}
+
+	if !hasFinalizer(instance) {
+		instance.Finalizers = append(instance.Finalizers, imageSigningRequestFinalizer)
+		return reconcile.Result{}, r.client.Update(context.TODO(), instance)
+	}
+
 	imageSigningRequestMetadataKey, _ := cache.MetaNamespaceKeyFunc(instance)
-	emptyPhase := imagesigningrequestsv1alpha1.ImageSigningRequestStatus{}.Phase
+	emptyPhase := imagesigningrequestsv1alpha2.ImageSigningRequestStatus{}.Phase
 	if instance.Status.Phase == emptyPhase {
 
-		//requestImageStreamTag := &imagev1.ImageStreamTag{}
-		//err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.ImageStreamTag, Namespace: instance.ObjectMeta.Namespace}, requestImageStreamTag)
-		//requestImageStreamTag, err := r.imageClient.ImageStreamTags(instance.ObjectMeta.Namespace).Get(instance.Spec.ImageStreamTag, metav1.GetOptions{})
-		instance.Status.EndTime = time.Time{}.String() // Need an initial value since time is not nullable
-		instance.Status.StartTime = time.Time{}.String()
+		// Requests that can never be signed fail instead of being retried
+		if problems := signing.ValidateSpec(instance.Spec); len(problems) > 0 {
+			errorMessage := strings.Join(problems, "; ")
+			logrus.Warnf(errorMessage)
+			err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInvalidRequest, errorMessage, *instance)
 
-		imageUrl, imageID, err := signing.GetImageLocationFromRequest(r.imageClient, instance.Spec.ContainerImage, instance.ObjectMeta.Namespace)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
 
-		if err != nil {
-			return reconcile.Result{}, err
+			return reconcile.Result{}, nil
 		}
 
-		logrus.Infof("No Signatures Exist on Image '%s'", imageID)
+		// Retrieve push secret if available
+		pushSecret := ""
+		if instance.Spec.PullSecret != nil {
+			pushSecret = instance.Spec.PullSecret.Name
+
+		}
 
 		// Setup default values
 		gpgSecretName := r.config.GpgSecret
 		gpgSignBy := r.config.GpgSignBy
 
+		gpgSecretRequested := instance.Spec.SigningKeySecretName
+		signingKeyName := ""
+		var signingKey *imagesigningrequestsv1alpha2.SigningKey
+
+		if instance.Spec.SigningKeyRef != nil {
+
+			// A SigningKey stores its key in the target project already, so there is no secret to copy
+			signingKeyName = instance.Spec.SigningKeyRef.Name
+			gpgSecretRequested = ""
+
+			signingKey = &imagesigningrequestsv1alpha2.SigningKey{}
+			err := r.client.Get(context.TODO(), types.NamespacedName{Name: signingKeyName, Namespace: instance.Namespace}, signingKey)
+
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+
+			if k8serrors.IsNotFound(err) || (signingKey.Status.CurrentKey == nil && signingKey.Status.Message != "") {
+
+				errorMessage := fmt.Sprintf("SigningKey '%s' Not Found in Namespace '%s'", signingKeyName, instance.Namespace)
+				if err == nil {
+					errorMessage = fmt.Sprintf("SigningKey '%s' in Namespace '%s' Has No Key: %s", signingKeyName, instance.Namespace, signingKey.Status.Message)
+				}
+				logrus.Warnf(errorMessage)
+				err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+
+			if signingKey.Status.CurrentKey == nil {
+				logrus.Infof("Waiting for SigningKey '%s' in Namespace '%s' to Generate a Key", signingKeyName, instance.Namespace)
+				return reconcile.Result{RequeueAfter: signingKeyRetryInterval}, nil
+			}
+
+			if err := signing.ValidateSigningKeySecret(signingKey); err != nil {
+				errorMessage := err.Error()
+				logrus.Warnf(errorMessage)
+				err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+
+			gpgSecretName = signingKey.Status.CurrentKey.SecretName
+			gpgSignBy = signingKey.Status.CurrentKey.Fingerprint
+
+		} else if instance.Spec.SigningKeySecretName != "" && instance.Spec.SigningKeySignBy != "" {
+			gpgSignBy = instance.Spec.SigningKeySignBy
+		}
+
+		// Requests for many images are signed within the operator one image at a time
+		if len(instance.Spec.ContainerImages) > 0 {
+			return r.signBatch(instance, signingKey, gpgSecretName, gpgSecretRequested, signingKeyName, gpgSignBy, pushSecret)
+		}
+
+		// Resolve the image to a digest once, everything after signs that digest even if the tag moves
+		location, err := signing.GetImageLocationFromRequest(r.client, r.imageClient, r.config, instance.Spec.ContainerImage, instance.ObjectMeta.Namespace, pushSecret)
+
+		if registry.IsNotFound(err) {
+			errorMessage := fmt.Sprintf("Image '%s' Not Found", instance.Spec.ContainerImage.Name)
+			logrus.Warnf(errorMessage)
+			err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		instance.Status.RequestedImage = location.Requested
+		instance.Status.ResolvedImage = location.Resolved
+
+		logrus.Infof("Resolved Image '%s' to '%s'", location.Requested, location.Resolved)
+
+		// Verify the namespace may sign this image with the requested key before any key material is copied
+		allowed, denyMessage, err := policy.Evaluate(r.client, policy.SigningIntent{
+			Namespace:  instance.Namespace,
+			SecretName: gpgSecretRequested,
+			SigningKey: signingKeyName,
+			KeyBackend: policyKeyBackend(instance, signingKey),
+			SignBy:     gpgSignBy,
+			Image:      location.Requested,
+		})
+
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if !allowed {
+			logrus.Warnf(denyMessage)
+			err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonPolicyDenied, denyMessage, *instance)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		// Every selected platform of a multi-architecture image is signed along with its manifest list
+		multiArch := registry.IsManifestList(location.MediaType)
+		if multiArch {
+
+			if instance.Spec.Executor == imagesigningrequestsv1alpha2.ExecutorPod {
+				errorMessage := fmt.Sprintf("Image '%s' is a multi-architecture image, which can only be signed by the inProcess executor", location.Requested)
+				logrus.Warnf(errorMessage)
+				err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInvalidRequest, errorMessage, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+
+			platforms, err := signing.ResolvePlatforms(r.client, r.config, instance.Namespace, location, pushSecret, instance.Spec.Platforms)
+
+			if err != nil {
+				errorMessage := fmt.Sprintf("Error Resolving Platforms of Image '%s': %v", location.Requested, err)
+				logrus.Warnf(errorMessage)
+				err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+
+			instance.Status.Platforms = platforms
+		}
+
+		// Keys held by a key backend never leave it, so they can only sign within the operator
+		keyBackend, keyBackendNamespace := r.keyBackend(instance, signingKey)
+
+		executor := instance.Spec.Executor
+		if executor == "" {
+			executor = r.config.SigningExecutor
+			if multiArch || keyBackend != nil {
+				executor = imagesigningrequestsv1alpha2.ExecutorInProcess
+			}
+		}
+
+		if errorMessage := keyBackendProblem(instance, keyBackend, executor); errorMessage != "" {
+			logrus.Warnf(errorMessage)
+			err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInvalidRequest, errorMessage, *instance)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{}, nil
+		}
+
+		keySecret := r.inProcessKeySecret(instance, gpgSecretName, gpgSecretRequested)
+
+		// The signer of simple signing signatures also holds the key existing signatures are checked against
+		var signer signing.Signer
+		var signerErr error
+		if instance.Spec.SignatureFormat != imagesigningrequestsv1alpha2.SignatureFormatCosign {
+			signer, signerErr = r.newSigner(instance, signingKey, keyBackend, keyBackendNamespace, keySecret, gpgSignBy)
+		}
+
+		// Images re-submitted by pipelines are not signed again with the same key unless forced
+		if signer != nil && !instance.Spec.Force {
+			signed, err := r.alreadySigned(instance, location, instance.Status.Platforms, signer, pushSecret)
+
+			if err != nil {
+				logrus.Infof("Existing Signatures of Image '%s' Not Checked: %v", location.Resolved, err)
+			}
+
+			if signed {
+				message := fmt.Sprintf("Image Already Signed by Key '%s'", signer.Fingerprint())
+				logrus.Infof("Image '%s' Already Signed by Key '%s'", location.Resolved, signer.Fingerprint())
+
+				for i := range instance.Status.Platforms {
+					instance.Status.Platforms[i].Signed = true
+				}
+
+				err = signing.UpdateOnAlreadySigned(r.client, message, location.Digest, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+		}
+
+		// Cosign signatures are always made within the operator
+		if executor == imagesigningrequestsv1alpha2.ExecutorInProcess || instance.Spec.SignatureFormat == imagesigningrequestsv1alpha2.SignatureFormatCosign {
+
+			err := signerErr
+
+			artifact := ""
+			if err == nil {
+				artifact, err = r.signInProcess(instance, location, instance.Status.Platforms, signer, keySecret, pushSecret)
+			}
+
+			if err != nil {
+				errorMessage := fmt.Sprintf("Error Occurred Signing Image '%v'", err)
+
+				logrus.Errorf(errorMessage)
+
+				err = signing.UpdateOnInProcessSigningFailure(r.client, errorMessage, *instance)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, nil
+			}
+
+			message := "Image Signed"
+			if artifact != "" {
+				instance.Status.SignatureArtifact = artifact
+				message = fmt.Sprintf("Image Signed, Signature Pushed to '%s'", artifact)
+			}
+
+			err = signing.UpdateOnInProcessSigningSuccess(r.client, message, location.Digest, *instance)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			return reconcile.Result{}, nil
+		}
+
 		// Check if Secret if found
-		if instance.Spec.SigningKeySecretName != "" {
+		if gpgSecretRequested != "" {
 
 			signingKeySecret := &corev1.Secret{}
 			err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.SigningKeySecretName, Namespace: instance.Namespace}, signingKeySecret)
@@ -134,7 +410,7 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 
 				errorMessage := fmt.Sprintf("GPG Secret '%s' Not Found in Namespace '%s'", instance.Spec.SigningKeySecretName, instance.Namespace)
 				logrus.Warnf(errorMessage)
-				err = signing.UpdateOnImageSigningInitializationFailure(r.client, errorMessage, *instance)
+				err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
 
 				if err != nil {
 					return reconcile.Result{}, err
@@ -146,7 +422,7 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 			logrus.Infof("Copying Secret '%s' to Project '%s'", instance.Spec.SigningKeySecretName, r.config.TargetProject)
 			// Create a copy
 			signingKeySecretCopy := signingKeySecret.DeepCopy()
-			signingKeySecretCopy.Name = string(instance.ObjectMeta.UID)
+			signingKeySecretCopy.Name = signing.KeySecretCopyName(instance)
 			signingKeySecretCopy.Namespace = r.config.TargetProject
 			signingKeySecretCopy.ResourceVersion = ""
 			signingKeySecretCopy.UID = ""
@@ -161,37 +437,30 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 
 			gpgSecretName = signingKeySecretCopy.Name
 
-			if instance.Spec.SigningKeySignBy != "" {
-				gpgSignBy = instance.Spec.SigningKeySignBy
-			}
-
-		}
-		// Retrieve push secret if available
-		pushSecret := ""
-		if instance.Spec.PullSecret != nil {
-			pushSecret = instance.Spec.PullSecret.Name
-
 		}
 
-		signingPodName, err := signing.LaunchSigningPod(r.client, r.scheme, r.config, instance, imageUrl, imageID, string(instance.ObjectMeta.UID), imageSigningRequestMetadataKey, gpgSecretName, gpgSignBy, pushSecret)
+		signingPodName, err := signing.LaunchSigningPod(r.client, r.scheme, r.config, instance, location.Requested, location.Resolved, location.Digest, string(instance.ObjectMeta.UID), imageSigningRequestMetadataKey, gpgSecretName, gpgSignBy, pushSecret)
 
 		if err != nil {
 			errorMessage := fmt.Sprintf("Error Occurred Creating Signing Pod '%v'", err)
 
 			logrus.Errorf(errorMessage)
 
-			err = signing.UpdateOnImageSigningInitializationFailure(r.client, errorMessage, *instance)
+			err = signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInitializationFailed, errorMessage, *instance)
 
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 
-			return reconcile.Result{}, nil
+			return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, instance)
 		}
 
 		logrus.Infof("Signing Pod Launched '%s'", signingPodName)
 
-		err = signing.UpdateOnSigningPodLaunch(r.client, fmt.Sprintf("Signing Pod Launched '%s'", signingPodName), imageID, *instance)
+		now := metav1.Now()
+		instance.Status.Attempts = append(instance.Status.Attempts, imagesigningrequestsv1alpha2.ImageSigningAttempt{Pod: signingPodName, StartTime: &now})
+
+		err = signing.UpdateOnSigningPodLaunch(r.client, fmt.Sprintf("Signing Pod Launched '%s'", signingPodName), location.Digest, *instance)
 
 		if err != nil {
 			return reconcile.Result{}, err
@@ -199,5 +468,358 @@ func (r *ReconcileImageSigningRequest) Reconcile(request reconcile.Request) (rec
 
 	}
 
+	// Only requests waiting on a signing pod are Running, they fail once their deadline passes
+	if instance.Status.Phase == images.PhaseRunning {
+		return r.enforceDeadline(instance)
+	}
+
 	return reconcile.Result{}, nil
 }
+
+// enforceDeadline fails a request whose signing pods have not signed the image within its active deadline, deleting
+// the pod of the current attempt. Requests within their deadline are checked again once it passes, which catches
+// pods that never start and so are not stopped by their own deadline.
+func (r *ReconcileImageSigningRequest) enforceDeadline(instance *imagesigningrequestsv1alpha2.ImageSigningRequest) (reconcile.Result, error) {
+
+	deadline, ok := signing.Deadline(instance, r.config)
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+
+	if wait := time.Until(deadline); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	if len(instance.Status.Attempts) > 0 {
+		podNamespace, podName, err := cache.SplitMetaNamespaceKey(instance.Status.Attempts[len(instance.Status.Attempts)-1].Pod)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		pod := &corev1.Pod{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: podNamespace}, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+
+		if err == nil {
+			// A pod stuck pending tells why its container never started
+			instance.Status.Failure = signing.PodFailure(pod, "")
+
+			if err := r.client.Delete(context.TODO(), pod); err != nil && !k8serrors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+
+			logrus.Infof("Deleted Signing Pod '%s' After the Deadline Passed", instance.Status.Attempts[len(instance.Status.Attempts)-1].Pod)
+		}
+	}
+
+	errorMessage := fmt.Sprintf("Image Not Signed Within the Deadline of %d Seconds", signing.ActiveDeadlineSeconds(instance, r.config))
+	if instance.Status.Failure != nil {
+		errorMessage = fmt.Sprintf("%s. %s", errorMessage, signing.FailureMessage(instance.Status.Failure))
+	}
+	logrus.Warnf(errorMessage)
+
+	if err := signing.UpdateOnDeadlineExceeded(r.client, errorMessage, *instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, instance)
+}
+
+// cleanUp deletes the key secret copy and every signing pod of a request being deleted from the target project, as
+// owner references cannot cross namespaces for them to be garbage collected
+func (r *ReconcileImageSigningRequest) cleanUp(instance *imagesigningrequestsv1alpha2.ImageSigningRequest) error {
+
+	if err := signing.DeleteKeySecretCopy(r.client, r.config, instance); err != nil {
+		return err
+	}
+
+	// The first pod is named after the request, even when no attempts were recorded
+	podKeys := []string{fmt.Sprintf("%s/%s", r.config.TargetProject, instance.UID)}
+	for _, attempt := range instance.Status.Attempts {
+		podKeys = append(podKeys, attempt.Pod)
+	}
+
+	for _, podKey := range podKeys {
+		podNamespace, podName, err := cache.SplitMetaNamespaceKey(podKey)
+		if err != nil {
+			return err
+		}
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: podNamespace}}
+		if err := r.client.Delete(context.TODO(), pod); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	logrus.Infof("Removed Signing Resources of Deleted ImageSigningRequest '%s/%s'", instance.Namespace, instance.Name)
+
+	return nil
+}
+
+func hasFinalizer(instance *imagesigningrequestsv1alpha2.ImageSigningRequest) bool {
+	for _, finalizer := range instance.Finalizers {
+		if finalizer == imageSigningRequestFinalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeFinalizer(instance *imagesigningrequestsv1alpha2.ImageSigningRequest) {
+	finalizers := []string{}
+	for _, finalizer := range instance.Finalizers {
+		if finalizer != imageSigningRequestFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	instance.Finalizers = finalizers
+}
+
+// inProcessKeySecret returns the secret holding the key of a request signed within the operator. The key is read
+// from wherever it is stored, so no copy of the secret is made.
+func (r *ReconcileImageSigningRequest) inProcessKeySecret(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, gpgSecretName string, gpgSecretRequested string) types.NamespacedName {
+	if gpgSecretRequested != "" {
+		return types.NamespacedName{Name: gpgSecretRequested, Namespace: instance.Namespace}
+	}
+
+	if instance.Spec.SignatureFormat == imagesigningrequestsv1alpha2.SignatureFormatCosign {
+		return types.NamespacedName{Name: r.config.CosignSecret, Namespace: r.config.TargetProject}
+	}
+
+	return types.NamespacedName{Name: gpgSecretName, Namespace: r.config.TargetProject}
+}
+
+// keyBackend returns the key backend selected by a request, its SigningKey or the operator configuration, along with
+// the namespace of the secrets the backend references. Nil is returned for keys stored in secrets. The default key
+// of cosign signatures is always the cosign secret of the operator, so the default key backend does not apply to them.
+func (r *ReconcileImageSigningRequest) keyBackend(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, signingKey *imagesigningrequestsv1alpha2.SigningKey) (*imagesigningrequestsv1alpha2.KeyBackend, string) {
+	if instance.Spec.KeyBackend != nil {
+		return instance.Spec.KeyBackend, instance.Namespace
+	}
+
+	if signingKey != nil {
+		return signingKey.Spec.KeyBackend, signingKey.Namespace
+	}
+
+	if instance.Spec.SigningKeySecretName == "" && instance.Spec.SignatureFormat != imagesigningrequestsv1alpha2.SignatureFormatCosign {
+		return signing.DefaultKeyBackend(r.config), r.config.TargetProject
+	}
+
+	return nil, ""
+}
+
+// policyKeyBackend returns the key backend selected by a request or its SigningKey, which signing policies must
+// allow. The default key backend of the operator is allowed along with the default key.
+func policyKeyBackend(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, signingKey *imagesigningrequestsv1alpha2.SigningKey) *imagesigningrequestsv1alpha2.KeyBackend {
+	if instance.Spec.KeyBackend != nil {
+		return instance.Spec.KeyBackend
+	}
+
+	if signingKey != nil {
+		return signingKey.Spec.KeyBackend
+	}
+
+	return nil
+}
+
+// keyBackendProblem explains why a request cannot be signed with the key backend selected for it, or returns an
+// empty string when it can. Keys held by a key backend never leave it, and only sign simple signing signatures.
+func keyBackendProblem(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, backend *imagesigningrequestsv1alpha2.KeyBackend, executor string) string {
+	if backend == nil {
+		return ""
+	}
+
+	if instance.Spec.SignatureFormat == imagesigningrequestsv1alpha2.SignatureFormatCosign {
+		return "Keys held by a key backend can only make simpleSigning signatures"
+	}
+
+	if executor == imagesigningrequestsv1alpha2.ExecutorPod {
+		return "Keys held by a key backend can only sign with the inProcess executor"
+	}
+
+	return ""
+}
+
+// newSigner returns the signer of the simple signing signatures of a request signed within the operator, which
+// signs with the key backend when one is selected and with the GPG key in keySecret otherwise
+func (r *ReconcileImageSigningRequest) newSigner(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, signingKey *imagesigningrequestsv1alpha2.SigningKey, backend *imagesigningrequestsv1alpha2.KeyBackend, namespace string, keySecret types.NamespacedName, signBy string) (signing.Signer, error) {
+
+	if backend == nil {
+		return signing.NewSecretSigner(r.client, keySecret, signBy)
+	}
+
+	// The identity of a SigningKey must match the one its published public key was issued to
+	if signingKey != nil {
+		return signing.NewBackendSigner(r.client, r.config, *backend, namespace, signingKey.Spec.Name, signingKey.Spec.Comment, signingKey.Spec.Email)
+	}
+
+	name, email := signing.Identity(signBy)
+	return signing.NewBackendSigner(r.client, r.config, *backend, namespace, name, "", email)
+}
+
+// alreadySigned reports whether the image at location, along with the given platforms of a multi-architecture image,
+// already carries a signature made by the key of the signer in the signature storage of the operator
+func (r *ReconcileImageSigningRequest) alreadySigned(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, location signing.ImageLocation, platforms []imagesigningrequestsv1alpha2.ImageSigningPlatformStatus, signer signing.Signer, pushSecret string) (bool, error) {
+
+	store, err := signing.SignatureSource(r.client, r.imageClient, r.config, instance.Namespace, pushSecret)
+	if err != nil {
+		return false, err
+	}
+
+	manifestDigests := []string{location.Digest}
+	for _, platform := range platforms {
+		manifestDigests = append(manifestDigests, platform.Digest)
+	}
+
+	return signing.AlreadySigned(store, signer.Keyring(), location.Requested, manifestDigests...)
+}
+
+// signInProcess signs the image at location, along with the given platforms of a multi-architecture image, within
+// the operator in the format requested. Simple signing signatures are made by the signer and cosign signatures with
+// the key in keySecret. The reference of the signature artifact is returned for cosign signatures.
+func (r *ReconcileImageSigningRequest) signInProcess(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, location signing.ImageLocation, platforms []imagesigningrequestsv1alpha2.ImageSigningPlatformStatus, signer signing.Signer, keySecret types.NamespacedName, pushSecret string) (string, error) {
+
+	if instance.Spec.SignatureFormat == imagesigningrequestsv1alpha2.SignatureFormatCosign {
+		artifact, err := signing.SignCosign(r.client, r.config, instance.Namespace, location.Requested, location.Digest, keySecret, pushSecret)
+		if err != nil {
+			return "", err
+		}
+
+		return artifact, signing.SignPlatforms(platforms, func(manifestDigest string) error {
+			_, err := signing.SignCosign(r.client, r.config, instance.Namespace, location.Requested, manifestDigest, keySecret, pushSecret)
+			return err
+		})
+	}
+
+	store, err := signing.SignatureStore(r.client, r.imageClient, r.config, instance.Namespace, pushSecret)
+	if err != nil {
+		return "", err
+	}
+
+	if err := signing.SignInProcess(store, signer, location.Requested, location.Digest); err != nil {
+		return "", err
+	}
+
+	return "", signing.SignPlatforms(platforms, func(manifestDigest string) error {
+		return signing.SignInProcess(store, signer, location.Requested, manifestDigest)
+	})
+}
+
+// signBatch signs every image of a request with containerImages within the operator, recording the result of each
+// image in the status. Images following a failed image are only signed with the Continue failure policy.
+func (r *ReconcileImageSigningRequest) signBatch(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, signingKey *imagesigningrequestsv1alpha2.SigningKey, gpgSecretName string, gpgSecretRequested string, signingKeyName string, gpgSignBy string, pushSecret string) (reconcile.Result, error) {
+
+	keySecret := r.inProcessKeySecret(instance, gpgSecretName, gpgSecretRequested)
+	keyBackend, keyBackendNamespace := r.keyBackend(instance, signingKey)
+
+	if errorMessage := keyBackendProblem(instance, keyBackend, imagesigningrequestsv1alpha2.ExecutorInProcess); errorMessage != "" {
+		logrus.Warnf(errorMessage)
+		return reconcile.Result{}, signing.UpdateOnImageSigningInitializationFailure(r.client, images.ReasonInvalidRequest, errorMessage, *instance)
+	}
+
+	var signer signing.Signer
+	if instance.Spec.SignatureFormat != imagesigningrequestsv1alpha2.SignatureFormatCosign {
+		var err error
+		signer, err = r.newSigner(instance, signingKey, keyBackend, keyBackendNamespace, keySecret, gpgSignBy)
+
+		if err != nil {
+			errorMessage := fmt.Sprintf("Error Occurred Loading Signing Key '%v'", err)
+			logrus.Errorf(errorMessage)
+			return reconcile.Result{}, signing.UpdateOnInProcessSigningFailure(r.client, errorMessage, *instance)
+		}
+	}
+
+	instance.Status.Images = make([]imagesigningrequestsv1alpha2.ImageSigningImageStatus, len(instance.Spec.ContainerImages))
+
+	failed := 0
+	for i, containerImage := range instance.Spec.ContainerImages {
+		imageStatus := &instance.Status.Images[i]
+		imageStatus.ContainerImage = containerImage
+
+		if failed > 0 && instance.Spec.FailurePolicy != imagesigningrequestsv1alpha2.FailurePolicyContinue {
+			imageStatus.Message = "Not Signed After an Earlier Image Failed"
+			continue
+		}
+
+		message, err := r.signBatchImage(instance, imageStatus, signer, keySecret, gpgSecretRequested, signingKeyName, policyKeyBackend(instance, signingKey), gpgSignBy, pushSecret)
+		if err != nil {
+			logrus.Warnf("Error Signing Image '%s': %v", containerImage.Name, err)
+			imageStatus.Phase = images.PhaseFailed
+			imageStatus.Message = err.Error()
+			failed++
+			continue
+		}
+
+		imageStatus.Phase = images.PhaseCompleted
+		imageStatus.Message = message
+	}
+
+	var err error
+	if failed > 0 {
+		err = signing.UpdateOnInProcessSigningFailure(r.client, fmt.Sprintf("%d of %d Images Could Not Be Signed", failed, len(instance.Spec.ContainerImages)), *instance)
+	} else {
+		err = signing.UpdateOnInProcessSigningSuccess(r.client, fmt.Sprintf("%d Images Signed", len(instance.Spec.ContainerImages)), "", *instance)
+	}
+
+	return reconcile.Result{}, err
+}
+
+// signBatchImage resolves, authorizes and signs one image of a request with containerImages. Images that already carry
+// a signature made by the signer are not signed again unless forced. A description of the result is returned.
+func (r *ReconcileImageSigningRequest) signBatchImage(instance *imagesigningrequestsv1alpha2.ImageSigningRequest, imageStatus *imagesigningrequestsv1alpha2.ImageSigningImageStatus, signer signing.Signer, keySecret types.NamespacedName, gpgSecretRequested string, signingKeyName string, keyBackend *imagesigningrequestsv1alpha2.KeyBackend, gpgSignBy string, pushSecret string) (string, error) {
+
+	location, err := signing.GetImageLocationFromRequest(r.client, r.imageClient, r.config, &imageStatus.ContainerImage, instance.Namespace, pushSecret)
+	if err != nil {
+		return "", err
+	}
+
+	imageStatus.RequestedImage = location.Requested
+	imageStatus.ResolvedImage = location.Resolved
+
+	allowed, denyMessage, err := policy.Evaluate(r.client, policy.SigningIntent{
+		Namespace:  instance.Namespace,
+		SecretName: gpgSecretRequested,
+		SigningKey: signingKeyName,
+		KeyBackend: keyBackend,
+		SignBy:     gpgSignBy,
+		Image:      location.Requested,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !allowed {
+		return "", fmt.Errorf("%s", denyMessage)
+	}
+
+	if registry.IsManifestList(location.MediaType) {
+		imageStatus.Platforms, err = signing.ResolvePlatforms(r.client, r.config, instance.Namespace, location, pushSecret, instance.Spec.Platforms)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if signer != nil && !instance.Spec.Force {
+		signed, err := r.alreadySigned(instance, location, imageStatus.Platforms, signer, pushSecret)
+		if err != nil {
+			logrus.Infof("Existing Signatures of Image '%s' Not Checked: %v", location.Resolved, err)
+		}
+		if signed {
+			for i := range imageStatus.Platforms {
+				imageStatus.Platforms[i].Signed = true
+			}
+			return "Image Already Signed", nil
+		}
+	}
+
+	imageStatus.SignatureArtifact, err = r.signInProcess(instance, location, imageStatus.Platforms, signer, keySecret, pushSecret)
+	if err != nil {
+		return "", err
+	}
+
+	return "Image Signed", nil
+}
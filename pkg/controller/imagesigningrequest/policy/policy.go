@@ -0,0 +1,224 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/signature/kms"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SigningIntent describes what an ImageSigningRequest is about to do
+type SigningIntent struct {
+	// Namespace of the ImageSigningRequest
+	Namespace string
+	// SecretName of the signing key within the namespace. Empty when the operator's default key is used.
+	SecretName string
+	// SigningKey is the name of the SigningKey within the namespace. Empty when a secret or the default key is used.
+	SigningKey string
+	// KeyBackend selected by the request or held by its SigningKey. Nil when a secret or the default key is used.
+	KeyBackend *v1alpha2.KeyBackend
+	// SignBy is the identity used to sign
+	SignBy string
+	// Image is the resolved location of the image to sign
+	Image string
+}
+
+// Evaluate checks the intent against every ImageSigningPolicy selecting its namespace. The intent is allowed when
+// any selecting policy allows it. Clusters without any ImageSigningPolicy allow every intent.
+// When denied, the returned message explains why.
+func Evaluate(c client.Client, intent SigningIntent) (bool, string, error) {
+
+	policies := &v1alpha2.ImageSigningPolicyList{}
+	if err := c.List(context.TODO(), policies); err != nil {
+		return false, "", err
+	}
+
+	if len(policies.Items) == 0 {
+		return true, "", nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: intent.Namespace}, namespace); err != nil {
+		return false, "", err
+	}
+
+	selected := 0
+	for _, policy := range policies.Items {
+		matches, err := selectsNamespace(policy, namespace)
+		if err != nil {
+			return false, "", err
+		}
+		if !matches {
+			continue
+		}
+		selected++
+
+		if allowsKey(policy, intent) && allowsRepository(policy, intent.Image) {
+			return true, "", nil
+		}
+	}
+
+	if selected == 0 {
+		return false, fmt.Sprintf("No ImageSigningPolicy allows signing in Namespace '%s'", intent.Namespace), nil
+	}
+
+	key := "the default signing key"
+	if intent.SigningKey != "" && intent.KeyBackend != nil {
+		key = fmt.Sprintf("SigningKey '%s' held by %s", intent.SigningKey, describeKeyBackend(*intent.KeyBackend))
+	} else if intent.SigningKey != "" {
+		key = fmt.Sprintf("SigningKey '%s'", intent.SigningKey)
+	} else if intent.KeyBackend != nil {
+		key = describeKeyBackend(*intent.KeyBackend)
+	} else if intent.SecretName != "" {
+		key = fmt.Sprintf("Secret '%s'", intent.SecretName)
+	}
+
+	return false, fmt.Sprintf("No ImageSigningPolicy allows signing Image '%s' with %s as '%s' in Namespace '%s'", intent.Image, key, intent.SignBy, intent.Namespace), nil
+}
+
+func selectsNamespace(policy v1alpha2.ImageSigningPolicy, namespace *corev1.Namespace) (bool, error) {
+	if policy.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return false, fmt.Errorf("Invalid namespaceSelector in ImageSigningPolicy '%s': %v", policy.Name, err)
+	}
+
+	return selector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+func allowsKey(policy v1alpha2.ImageSigningPolicy, intent SigningIntent) bool {
+	// A SigningKey held by a key backend must be allowed along with its backend, so that a SigningKey cannot be
+	// pointed at a key the policy does not allow
+	if intent.SigningKey != "" {
+		for _, key := range policy.Spec.AllowedKeys {
+			if key.SigningKey == intent.SigningKey {
+				return intent.KeyBackend == nil || allowsKeyBackend(policy, *intent.KeyBackend, "")
+			}
+		}
+		return false
+	}
+
+	// Keys of a key backend are only allowed when listed, never as the default key
+	if intent.KeyBackend != nil {
+		return allowsKeyBackend(policy, *intent.KeyBackend, intent.SignBy)
+	}
+
+	if intent.SecretName == "" {
+		return policy.Spec.AllowDefaultKey
+	}
+
+	for _, key := range policy.Spec.AllowedKeys {
+		if key.SecretName == intent.SecretName && allowsSignBy(key, intent.SignBy) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsKeyBackend returns whether a key of a key backend is listed by a policy. The identity of SigningKeys is fixed,
+// so an empty signBy skips checking it.
+func allowsKeyBackend(policy v1alpha2.ImageSigningPolicy, backend v1alpha2.KeyBackend, signBy string) bool {
+	for _, key := range policy.Spec.AllowedKeys {
+		if key.KeyBackend != nil && matchesKeyBackend(*key.KeyBackend, backend) && (signBy == "" || allowsSignBy(key, signBy)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func allowsSignBy(key v1alpha2.ImageSigningPolicyKey, signBy string) bool {
+	if len(key.SignBy) == 0 {
+		return true
+	}
+
+	for _, allowed := range key.SignBy {
+		if allowed == signBy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesKeyBackend returns whether the key backend selected by a request is one of the keys identified by a policy.
+// Empty optional fields of the policy match any value.
+func matchesKeyBackend(allowed v1alpha2.ImageSigningPolicyKeyBackend, backend v1alpha2.KeyBackend) bool {
+
+	if allowed.Vault != nil && backend.Vault != nil {
+		mount := backend.Vault.Mount
+		if mount == "" {
+			mount = kms.DefaultVaultTransitMount
+		}
+
+		if matchesOptional(allowed.Vault.Address, backend.Vault.Address) && matchesOptional(allowed.Vault.Mount, mount) && allowed.Vault.KeyName == backend.Vault.KeyName {
+			return true
+		}
+	}
+
+	if allowed.PKCS11 != nil && backend.PKCS11 != nil {
+		if allowed.PKCS11.TokenLabel == backend.PKCS11.TokenLabel && matchesOptional(allowed.PKCS11.KeyID, backend.PKCS11.KeyID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesOptional(allowed string, value string) bool {
+	return allowed == "" || allowed == value
+}
+
+func describeKeyBackend(backend v1alpha2.KeyBackend) string {
+	switch {
+	case backend.Vault != nil:
+		return fmt.Sprintf("Vault transit key '%s'", backend.Vault.KeyName)
+	case backend.PKCS11 != nil:
+		return fmt.Sprintf("PKCS#11 key '%s' of token '%s'", backend.PKCS11.KeyID, backend.PKCS11.TokenLabel)
+	}
+
+	return "a key backend"
+}
+
+func allowsRepository(policy v1alpha2.ImageSigningPolicy, image string) bool {
+	if len(policy.Spec.AllowedRepositories) == 0 {
+		return true
+	}
+
+	repository := RepositoryFromImage(image)
+
+	for _, allowed := range policy.Spec.AllowedRepositories {
+		if strings.HasSuffix(allowed, "*") {
+			if strings.HasPrefix(repository, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+		} else if repository == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RepositoryFromImage strips the tag or digest from an image location, returning the fully qualified repository.
+// Locations that are not valid image references are returned unchanged.
+func RepositoryFromImage(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	return reference.TrimNamed(named).Name()
+}
@@ -0,0 +1,98 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/redhat-cop/image-security/pkg/signature/cosign"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SignCosign signs the manifest digest of an image in the format read by cosign and pushes the signature to the
+// sha256-<hex>.sig tag in the repository of the image, alongside any signatures already there. The reference of
+// the signature artifact is returned.
+func SignCosign(c client.Client, config config.Config, namespace string, image string, signedDigest string, keySecret types.NamespacedName, pullSecret string) (string, error) {
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	manifestDigest, err := digest.Parse(signedDigest)
+	if err != nil {
+		return "", fmt.Errorf("Invalid digest '%s': %v", signedDigest, err)
+	}
+
+	credentials, err := RegistryCredentials(c, config, namespace, pullSecret)
+	if err != nil {
+		return "", err
+	}
+
+	registryClient := registry.NewClient(credentials, config.RegistryTLSVerify)
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), keySecret, secret); err != nil {
+		return "", fmt.Errorf("Error retrieving cosign Secret '%s' in Namespace '%s': %v", keySecret.Name, keySecret.Namespace, err)
+	}
+
+	signer, err := cosign.LoadPrivateKey(secret.Data[cosign.PrivateKeyKey], secret.Data[cosign.PasswordKey])
+	if err != nil {
+		return "", fmt.Errorf("Error reading cosign Secret '%s': %v", keySecret.Name, err)
+	}
+
+	payload, err := cosign.NewPayload(ref.Name(), manifestDigest)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := cosign.Sign(signer, payload)
+	if err != nil {
+		return "", err
+	}
+
+	tag := cosign.SignatureTag(manifestDigest)
+	signatureRef, err := reference.WithTag(reference.TrimNamed(ref), tag)
+	if err != nil {
+		return "", err
+	}
+
+	existing, _, err := registryClient.GetManifest(signatureRef, tag)
+	if err != nil && !registry.IsNotFound(err) {
+		return "", err
+	}
+
+	manifest, signatureConfig, err := cosign.AppendSignature(existing, payload, signature)
+	if err != nil {
+		return "", err
+	}
+
+	artifactDigest := digest.FromBytes(existing)
+
+	if manifest != nil {
+		if _, err := registryClient.PushBlob(signatureRef, payload); err != nil {
+			return "", err
+		}
+
+		if _, err := registryClient.PushBlob(signatureRef, signatureConfig); err != nil {
+			return "", err
+		}
+
+		artifactDigest, err = registryClient.PutManifest(signatureRef, tag, cosign.MediaTypeOCIManifest, manifest)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	artifact := fmt.Sprintf("%s@%s", signatureRef.String(), artifactDigest)
+
+	logrus.Infof("Pushed Cosign Signature '%s' for Image '%s'", artifact, image)
+
+	return artifact, nil
+}
@@ -0,0 +1,141 @@
+package signing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Limits on what is kept of the output of a failed signing pod
+const (
+	FailureLogLines         = 20
+	maxFailureLogLength     = 2048
+	maxFailureMessageLength = 512
+	maxFailureDetailLength  = 200
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	escapeSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	controlChars   = regexp.MustCompile(`[\x00-\x08\x0b-\x1f\x7f]`)
+	privateBlock   = regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*(PRIVATE KEY|PRIVATE KEY BLOCK)-----.*?(-----END [A-Z0-9 ]*-----|$)`)
+	credentialFlag = regexp.MustCompile(`(?i)((?:^|\s)--?(?:password|passphrase|creds|token|p)(?:\s+|=))\S+`)
+	credentialPair = regexp.MustCompile(`(?i)((?:password|passwd|passphrase|secret|token|auth)["']?\s*[:=]\s*["']?)[^\s"',}]+`)
+	authorization  = regexp.MustCompile(`(?i)((?:bearer|basic)\s+)[A-Za-z0-9._~+/=-]+`)
+	urlUserinfo    = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+)
+
+// PodFailure describes the failure of a signing pod from the state of its signing container and the tail of its
+// log. Anything that looks like a credential is redacted, since the failure is shown to the requester.
+func PodFailure(pod *corev1.Pod, logTail string) *v1alpha2.ImageSigningFailure {
+
+	podMetadataKey, _ := cache.MetaNamespaceKeyFunc(pod)
+
+	failure := &v1alpha2.ImageSigningFailure{
+		Pod:     podMetadataKey,
+		Reason:  pod.Status.Reason,
+		Message: pod.Status.Message,
+		LogTail: Sanitize(logTail, FailureLogLines, maxFailureLogLength),
+	}
+
+	if len(pod.Status.ContainerStatuses) > 0 {
+		state := pod.Status.ContainerStatuses[0].State
+
+		switch {
+		case state.Terminated != nil:
+			failure.ExitCode = state.Terminated.ExitCode
+			if state.Terminated.Reason != "" {
+				failure.Reason = state.Terminated.Reason
+			}
+			if state.Terminated.Message != "" {
+				failure.Message = state.Terminated.Message
+			}
+
+		// Containers that never started, such as those whose image cannot be pulled, are only waiting
+		case state.Waiting != nil && state.Waiting.Reason != "":
+			failure.Reason = state.Waiting.Reason
+			if state.Waiting.Message != "" {
+				failure.Message = state.Waiting.Message
+			}
+		}
+	}
+
+	failure.Message = Sanitize(failure.Message, FailureLogLines, maxFailureMessageLength)
+
+	return failure
+}
+
+// FailureMessage summarizes the failure of a signing pod for the conditions of a request, ending with the last line
+// of output explaining it
+func FailureMessage(failure *v1alpha2.ImageSigningFailure) string {
+
+	message := fmt.Sprintf("Signing Pod '%s' Failed", failure.Pod)
+
+	switch {
+	case failure.ExitCode != 0 && failure.Reason != "":
+		message = fmt.Sprintf("%s with Exit Code %d (%s)", message, failure.ExitCode, failure.Reason)
+	case failure.ExitCode != 0:
+		message = fmt.Sprintf("%s with Exit Code %d", message, failure.ExitCode)
+	case failure.Reason != "":
+		message = fmt.Sprintf("%s (%s)", message, failure.Reason)
+	}
+
+	detail := lastLine(failure.Message)
+	if detail == "" {
+		detail = lastLine(failure.LogTail)
+	}
+
+	if detail != "" {
+		message = fmt.Sprintf("%s: %s", message, truncate(detail, maxFailureDetailLength))
+	}
+
+	return message
+}
+
+// Sanitize prepares output of a signing pod to be shown to the requester. Terminal escape sequences and credentials
+// are removed, and only the last lines are kept, up to maxLength bytes.
+func Sanitize(output string, maxLines int, maxLength int) string {
+
+	output = escapeSequence.ReplaceAllString(output, "")
+	output = strings.Replace(output, "\r\n", "\n", -1)
+	output = controlChars.ReplaceAllString(output, "")
+
+	output = privateBlock.ReplaceAllString(output, redacted)
+	output = credentialFlag.ReplaceAllString(output, "${1}"+redacted)
+	output = credentialPair.ReplaceAllString(output, "${1}"+redacted)
+	output = authorization.ReplaceAllString(output, "${1}"+redacted)
+	output = urlUserinfo.ReplaceAllString(output, "${1}"+redacted+"@")
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	output = strings.Join(lines, "\n")
+
+	// The end of the output is kept, as that is where the cause of a failure is reported
+	if len(output) > maxLength {
+		output = strings.ToValidUTF8(output[len(output)-maxLength:], "")
+	}
+
+	return output
+}
+
+// lastLine returns the last line of output that is not blank
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// truncate shortens a message to at most maxLength bytes
+func truncate(message string, maxLength int) string {
+	if len(message) <= maxLength {
+		return message
+	}
+
+	return strings.ToValidUTF8(message[:maxLength], "") + "..."
+}
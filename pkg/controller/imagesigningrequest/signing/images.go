@@ -2,56 +2,213 @@ package signing
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
+	"github.com/docker/distribution/reference"
 	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetImageLocationFromRequest(client *imageset.ImageV1Client, image *kapi.ObjectReference, namespace string) (string, string, error) {
-	if image != nil && (image.Kind == "ImageStreamImage") {
-		dockerImageComponents := strings.Split(image.Name, "@")
+// ValidateSpec returns a description of every problem with the spec of a request that would prevent it from
+// ever being signed
+func ValidateSpec(spec v1alpha2.ImageSigningRequestSpec) []string {
+	problems := []string{}
 
-		if len(dockerImageComponents) != 2 {
-			return "", "", errors.New("Unexpected ImageStreamImage Reference")
+	if len(spec.ContainerImages) == 0 {
+		if err := ValidateImageReference(spec.ContainerImage); err != nil {
+			problems = append(problems, err.Error())
 		}
+	} else {
+		if spec.ContainerImage != nil {
+			problems = append(problems, "containerImage cannot be used together with containerImages")
+		}
+		for i := range spec.ContainerImages {
+			if err := ValidateImageReference(&spec.ContainerImages[i]); err != nil {
+				problems = append(problems, fmt.Sprintf("containerImages[%d]: %v", i, err))
+			}
+		}
+		if spec.Executor == v1alpha2.ExecutorPod {
+			problems = append(problems, "containerImages can only be signed by the inProcess executor")
+		}
+	}
 
-		return image.Name, dockerImageComponents[1], nil
+	if spec.SigningKeyRef != nil && spec.SigningKeySecretName != "" {
+		problems = append(problems, "signingKeyRef cannot be used together with signingKeySecretName")
 	}
 
-	if image != nil && (image.Kind == "ImageStreamTag") {
-		requestImageStreamTag, err := client.ImageStreamTags(namespace).Get(image.Name, metav1.GetOptions{})
-		dockerImageComponents := strings.Split(requestImageStreamTag.Image.DockerImageReference, "@")
+	if spec.SignatureFormat == v1alpha2.SignatureFormatCosign {
+		if spec.SigningKeyRef != nil {
+			problems = append(problems, "SigningKeys hold GPG keys and cannot produce cosign signatures")
+		}
+		if spec.Executor == v1alpha2.ExecutorPod {
+			problems = append(problems, "cosign signatures can only be made by the inProcess executor")
+		}
+	}
 
-		if len(dockerImageComponents) != 2 {
-			return "", "", errors.New("Unexpected ImageStreamTag Reference")
+	if spec.KeyBackend != nil {
+		if err := ValidateKeyBackend(*spec.KeyBackend); err != nil {
+			problems = append(problems, err.Error())
 		}
+		if spec.SigningKeyRef != nil || spec.SigningKeySecretName != "" {
+			problems = append(problems, "keyBackend cannot be used together with signingKeyRef or signingKeySecretName")
+		}
+		if spec.SignatureFormat == v1alpha2.SignatureFormatCosign {
+			problems = append(problems, "keys held by a keyBackend can only make simpleSigning signatures")
+		}
+		if spec.Executor == v1alpha2.ExecutorPod {
+			problems = append(problems, "keys held by a keyBackend can only sign with the inProcess executor")
+		}
+	}
+
+	for _, platform := range spec.Platforms {
+		parts := strings.Split(platform, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			problems = append(problems, fmt.Sprintf("platform '%s' must be in the form os/architecture[/variant]", platform))
+		}
+	}
+
+	if spec.ActiveDeadlineSeconds != nil && *spec.ActiveDeadlineSeconds < 1 {
+		problems = append(problems, "activeDeadlineSeconds must be at least 1")
+	}
+
+	if spec.TTLSecondsAfterFinished != nil && *spec.TTLSecondsAfterFinished < 0 {
+		problems = append(problems, "ttlSecondsAfterFinished cannot be negative")
+	}
+
+	if spec.RetryPolicy != nil {
+		if spec.RetryPolicy.MaxAttempts < 0 {
+			problems = append(problems, "retryPolicy.maxAttempts must be at least 1")
+		}
+		if spec.RetryPolicy.Backoff != nil && spec.RetryPolicy.Backoff.Duration < 0 {
+			problems = append(problems, "retryPolicy.backoff cannot be negative")
+		}
+	}
+
+	if len(spec.Platforms) > 0 && spec.Executor == v1alpha2.ExecutorPod {
+		problems = append(problems, "multi-architecture images can only be signed by the inProcess executor")
+	}
+
+	return problems
+}
+
+// ValidateImageReference checks that a containerImage reference is in a form that GetImageLocationFromRequest
+// can resolve. The returned error describes the problem in terms of the request.
+func ValidateImageReference(image *kapi.ObjectReference) error {
+	if image == nil {
+		return errors.New("containerImage is required")
+	}
 
+	if image.Name == "" {
+		return errors.New("containerImage.name is required")
+	}
+
+	switch image.Kind {
+	case "ImageStreamImage":
+		components := strings.Split(image.Name, "@")
+		if len(components) != 2 || components[0] == "" || components[1] == "" {
+			return fmt.Errorf("ImageStreamImage '%s' must be in the form <imagestream>@<digest>", image.Name)
+		}
+	case "ImageStreamTag":
+		components := strings.Split(image.Name, ":")
+		if len(components) != 2 || components[0] == "" || components[1] == "" {
+			return fmt.Errorf("ImageStreamTag '%s' must be in the form <imagestream>:<tag>", image.Name)
+		}
+	case "ContainerRepository":
+		// registry.ParseReference adds the default tag, so the name is checked as written
+		named, err := reference.ParseNormalizedNamed(image.Name)
 		if err != nil {
-			return "", "", errors.New("Error finding ImageStreamTag")
+			return fmt.Errorf("Invalid image reference '%s': %v", image.Name, err)
 		}
+		_, tagged := named.(reference.Tagged)
+		_, digested := named.(reference.Digested)
+		if !tagged && !digested {
+			return fmt.Errorf("ContainerRepository '%s' must include a tag or digest", image.Name)
+		}
+	default:
+		return fmt.Errorf("Unsupported containerImage kind '%s', expected ImageStreamTag, ImageStreamImage or ContainerRepository", image.Kind)
+	}
+
+	return nil
+}
 
-		return requestImageStreamTag.Image.DockerImageReference, dockerImageComponents[1], nil
+// ImageLocation identifies the image a request was made for
+type ImageLocation struct {
+	// Requested is the reference of the image as requested, which signatures are made for
+	Requested string
+	// Resolved references the manifest digest the requested reference pointed to when it was resolved. Images are
+	// always pulled and signed by this reference so that a tag moving mid-request cannot change what is signed.
+	Resolved string
+	// Digest is the resolved manifest digest
+	Digest string
+	// MediaType of the resolved manifest, which tells manifest lists apart from the manifests of single images
+	MediaType string
+}
 
+// GetImageLocationFromRequest resolves the image referenced by a request to the digest of its manifest. Tags of
+// ContainerRepository images are resolved against their registry using the credentials of the pull secret.
+func GetImageLocationFromRequest(c client.Client, imageClient *imageset.ImageV1Client, config config.Config, image *kapi.ObjectReference, namespace string, pullSecret string) (ImageLocation, error) {
+	if err := ValidateImageReference(image); err != nil {
+		return ImageLocation{}, err
 	}
-	if image != nil && (image.Kind == "ContainerRepository") {
-		// Check if its in digest form
-		dockerImageComponents := strings.Split(image.Name, "@")
-		if len(dockerImageComponents) > 1 {
-			SHAComponents := strings.Split(image.Name, ":")
-			if len(SHAComponents) > 1 {
-				return image.Name, SHAComponents[1], nil
-			}
+
+	switch image.Kind {
+	case "ImageStreamImage":
+		requestImageStreamImage, err := imageClient.ImageStreamImages(namespace).Get(image.Name, metav1.GetOptions{})
+		if err != nil {
+			return ImageLocation{}, fmt.Errorf("Error finding ImageStreamImage '%s': %v", image.Name, err)
 		}
-		// Check if its in tag form
-		dockerImageComponents = strings.Split(image.Name, ":")
-		if len(dockerImageComponents) > 1 {
 
-			return image.Name, dockerImageComponents[0], nil
+		return locationFromDigestReference(requestImageStreamImage.Image.DockerImageReference, requestImageStreamImage.Image.DockerImageManifestMediaType)
+
+	case "ImageStreamTag":
+		requestImageStreamTag, err := imageClient.ImageStreamTags(namespace).Get(image.Name, metav1.GetOptions{})
+		if err != nil {
+			return ImageLocation{}, fmt.Errorf("Error finding ImageStreamTag '%s': %v", image.Name, err)
 		}
 
+		return locationFromDigestReference(requestImageStreamTag.Image.DockerImageReference, requestImageStreamTag.Image.DockerImageManifestMediaType)
+	}
+
+	ref, err := registry.ParseReference(image.Name)
+	if err != nil {
+		return ImageLocation{}, err
+	}
+
+	credentials, err := RegistryCredentials(c, config, namespace, pullSecret)
+	if err != nil {
+		return ImageLocation{}, err
+	}
+
+	manifestDigest, mediaType, err := registry.NewClient(credentials, config.RegistryTLSVerify).ResolveDigest(ref)
+	if err != nil {
+		return ImageLocation{}, err
+	}
+
+	resolved, err := reference.WithDigest(reference.TrimNamed(ref), manifestDigest)
+	if err != nil {
+		return ImageLocation{}, err
+	}
+
+	return ImageLocation{Requested: ref.String(), Resolved: resolved.String(), Digest: manifestDigest.String(), MediaType: mediaType}, nil
+}
+
+// locationFromDigestReference returns the location of an image known to the cluster by a reference to its digest
+func locationFromDigestReference(dockerImageReference string, mediaType string) (ImageLocation, error) {
+	ref, err := registry.ParseReference(dockerImageReference)
+	if err != nil {
+		return ImageLocation{}, err
+	}
+
+	digested, ok := ref.(reference.Digested)
+	if !ok {
+		return ImageLocation{}, fmt.Errorf("Image reference '%s' does not reference a digest", dockerImageReference)
 	}
 
-	return "", "", errors.New("Unexpected Image Reference")
+	return ImageLocation{Requested: dockerImageReference, Resolved: dockerImageReference, Digest: digested.Digest().String(), MediaType: mediaType}, nil
 }
@@ -0,0 +1,127 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/redhat-cop/image-security/pkg/signature/storage"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// signatureCreator is recorded in the optional section of signatures made within the operator
+const signatureCreator = "image-security operator"
+
+// SignInProcess signs the manifest digest of an image within the operator, without accessing the registry. The
+// signature is made for the image reference by the signer and written to the store.
+func SignInProcess(store storage.Store, signer Signer, image string, manifestDigest string) error {
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	signedDigest, err := digest.Parse(manifestDigest)
+	if err != nil {
+		return fmt.Errorf("Invalid digest '%s': %v", manifestDigest, err)
+	}
+
+	signed, err := signer.Sign(signature.NewPayload(ref.String(), signedDigest, signatureCreator, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	if err := store.Put(ref, signedDigest, signed); err != nil {
+		return err
+	}
+
+	logrus.Infof("Signed Image '%s' with Digest '%s' In Process Using Key '%s'", image, manifestDigest, signer.Fingerprint())
+
+	return nil
+}
+
+// AlreadySigned reports whether every manifest digest of an image already carries a valid signature made by a key of
+// the keyring for the repository of the image
+func AlreadySigned(store storage.Store, keyring openpgp.EntityList, image string, manifestDigests ...string) (bool, error) {
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return false, err
+	}
+
+	for _, manifestDigest := range manifestDigests {
+		signedDigest, err := digest.Parse(manifestDigest)
+		if err != nil {
+			return false, fmt.Errorf("Invalid digest '%s': %v", manifestDigest, err)
+		}
+
+		signatures, err := store.Signatures(ref, signedDigest)
+		if err != nil {
+			return false, err
+		}
+
+		if !hasValidSignature(signatures, keyring, ref, signedDigest) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func hasValidSignature(signatures [][]byte, keyring openpgp.EntityList, ref reference.Named, manifestDigest digest.Digest) bool {
+	for _, candidate := range signatures {
+		if _, err := signature.Verify(candidate, keyring, ref, manifestDigest); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegistryCredentials returns the credentials for accessing the registry of an image. The pull secret of the
+// request is used when given, otherwise the pull secrets of the operator's service account are used, matching
+// the behaviour of the signing pod.
+func RegistryCredentials(c client.Client, config config.Config, namespace string, pullSecret string) (registry.Credentials, error) {
+
+	if pullSecret != "" {
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: pullSecret, Namespace: namespace}, secret); err != nil {
+			return nil, fmt.Errorf("Error retrieving pull secret '%s' in Namespace '%s': %v", pullSecret, namespace, err)
+		}
+
+		return registry.CredentialsFromSecret(secret)
+	}
+
+	serviceAccount := &corev1.ServiceAccount{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: config.TargetServiceAccount, Namespace: config.TargetProject}, serviceAccount); err != nil {
+		return nil, fmt.Errorf("Error retrieving ServiceAccount '%s' in Namespace '%s': %v", config.TargetServiceAccount, config.TargetProject, err)
+	}
+
+	credentials := registry.Credentials{}
+	for _, reference := range serviceAccount.ImagePullSecrets {
+		secret := &corev1.Secret{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: reference.Name, Namespace: config.TargetProject}, secret); err != nil {
+			logrus.Warnf("Error retrieving pull secret '%s' in Namespace '%s': %v", reference.Name, config.TargetProject, err)
+			continue
+		}
+
+		secretCredentials, err := registry.CredentialsFromSecret(secret)
+		if err != nil {
+			logrus.Warnf(err.Error())
+			continue
+		}
+
+		credentials.Merge(secretCredentials)
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,96 @@
+package signing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolvePlatforms lists the platforms of a multi-architecture image that are selected by the filter. Entries of
+// the manifest list without a platform, or with the unknown platform used for attestations, are not signed.
+func ResolvePlatforms(c client.Client, config config.Config, namespace string, location ImageLocation, pullSecret string, filter []string) ([]v1alpha2.ImageSigningPlatformStatus, error) {
+
+	ref, err := registry.ParseReference(location.Resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := RegistryCredentials(c, config, namespace, pullSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, _, err := registry.NewClient(credentials, config.RegistryTLSVerify).GetManifest(ref, location.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := registry.ParseManifestList(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	platforms := []v1alpha2.ImageSigningPlatformStatus{}
+	for _, descriptor := range list.Manifests {
+		if descriptor.Platform == nil || descriptor.Platform.OS == "unknown" {
+			continue
+		}
+
+		if !selectsPlatform(filter, *descriptor.Platform) {
+			continue
+		}
+
+		platforms = append(platforms, v1alpha2.ImageSigningPlatformStatus{
+			Platform: descriptor.Platform.String(),
+			Digest:   descriptor.Digest.String(),
+		})
+	}
+
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("None of the platforms '%s' are in the manifest list of Image '%s'", strings.Join(filter, ", "), location.Requested)
+	}
+
+	return platforms, nil
+}
+
+// SignPlatforms signs the manifest of every platform with sign, recording the result of each platform in its
+// status. The returned error describes every platform that could not be signed.
+func SignPlatforms(platforms []v1alpha2.ImageSigningPlatformStatus, sign func(manifestDigest string) error) error {
+	failures := []string{}
+
+	for i := range platforms {
+		if err := sign(platforms[i].Digest); err != nil {
+			platforms[i].Signed = false
+			platforms[i].Message = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", platforms[i].Platform, err))
+			continue
+		}
+
+		platforms[i].Signed = true
+		platforms[i].Message = ""
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("Error signing platforms: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func selectsPlatform(filter []string, platform registry.Platform) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, selected := range filter {
+		if platform.Matches(selected) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,247 @@
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/redhat-cop/image-security/pkg/signature/kms"
+	"golang.org/x/crypto/openpgp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys of the secrets holding the credentials of key backends
+const (
+	VaultTokenKey = "token"
+	PKCS11PinKey  = "pin"
+)
+
+// Signer makes the simple signing signatures of images. Signers backed by a key backend never hold the private key
+// themselves, only the digest of a payload is handed to the backend.
+type Signer interface {
+	// Sign returns the OpenPGP signed message over a payload, which is stored as the signature
+	Sign(payload signature.Payload) ([]byte, error)
+	// Fingerprint of the OpenPGP key signatures are made with
+	Fingerprint() string
+	// Keyring holds the public key signatures are made with, to verify existing signatures against
+	Keyring() openpgp.EntityList
+}
+
+// entitySigner signs with an OpenPGP key, whose private key may be held by a key backend
+type entitySigner struct {
+	entity *openpgp.Entity
+}
+
+func (s *entitySigner) Sign(payload signature.Payload) ([]byte, error) {
+	return signature.Sign(payload, s.entity)
+}
+
+func (s *entitySigner) Fingerprint() string {
+	return fmt.Sprintf("%X", s.entity.PrimaryKey.Fingerprint)
+}
+
+func (s *entitySigner) Keyring() openpgp.EntityList {
+	return openpgp.EntityList{s.entity}
+}
+
+// NewSecretSigner returns a signer for the GPG key identified by signBy in the secret keyring of a secret
+func NewSecretSigner(c client.Client, keySecret types.NamespacedName, signBy string) (Signer, error) {
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), keySecret, secret); err != nil {
+		return nil, fmt.Errorf("Error retrieving GPG Secret '%s' in Namespace '%s': %v", keySecret.Name, keySecret.Namespace, err)
+	}
+
+	keyring, err := signature.Keyring(secret.Data[common.GpgSecretKeyringKey])
+	if err != nil {
+		return nil, fmt.Errorf("Error reading GPG Secret '%s': %v", keySecret.Name, err)
+	}
+
+	entity, err := signature.SigningEntity(keyring, signBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entitySigner{entity: entity}, nil
+}
+
+// NewBackendSigner returns a signer for a key held by a key backend, wrapped in an OpenPGP key issued to the given
+// identity. The secrets the backend references are read from namespace.
+func NewBackendSigner(c client.Client, configuration config.Config, backend v1alpha2.KeyBackend, namespace string, name string, comment string, email string) (Signer, error) {
+
+	key, err := BackendKey(c, configuration, backend, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := signature.NewEntity(key, name, comment, email, key.CreationTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entitySigner{entity: entity}, nil
+}
+
+// BackendKey connects to the key held by a key backend, reading the credentials it references from namespace. Only
+// the addresses of Vault and the PKCS#11 library configured for the operator are used, as backends are named by
+// requesters.
+func BackendKey(c client.Client, configuration config.Config, backend v1alpha2.KeyBackend, namespace string) (kms.Key, error) {
+
+	if err := ValidateKeyBackend(backend); err != nil {
+		return nil, err
+	}
+
+	if vault := backend.Vault; vault != nil {
+		address := vault.Address
+		if address == "" {
+			address = configuration.VaultAddress
+		}
+
+		if !VaultAddressAllowed(configuration, address) {
+			return nil, fmt.Errorf("Vault address '%s' is not allowed by the operator", address)
+		}
+
+		token, err := secretValue(c, types.NamespacedName{Name: vault.TokenSecretName, Namespace: namespace}, VaultTokenKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return kms.NewVaultTransitKey(address, vault.Mount, vault.KeyName, token, true)
+	}
+
+	if configuration.PKCS11Module == "" {
+		return nil, errors.New("No PKCS#11 module is configured for the operator")
+	}
+
+	pkcs11 := backend.PKCS11
+	pin, err := secretValue(c, types.NamespacedName{Name: pkcs11.PinSecretName, Namespace: namespace}, PKCS11PinKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return kms.NewPKCS11Key(configuration.PKCS11Module, pkcs11.TokenLabel, pkcs11.KeyID, pin)
+}
+
+// VaultAddressAllowed returns whether key backends may reference Vault at address, which must be the address of Vault
+// configured for the operator or one of its allowed addresses
+func VaultAddressAllowed(configuration config.Config, address string) bool {
+
+	if address == "" {
+		return false
+	}
+
+	allowed := append([]string{configuration.VaultAddress}, configuration.VaultAllowedAddresses...)
+	for _, candidate := range allowed {
+		if strings.TrimSuffix(candidate, "/") == strings.TrimSuffix(address, "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateKeyBackend checks that exactly one backend is set along with the fields it requires
+func ValidateKeyBackend(backend v1alpha2.KeyBackend) error {
+
+	switch {
+	case backend.Vault != nil && backend.PKCS11 != nil:
+		return errors.New("keyBackend must set only one of vault and pkcs11")
+
+	case backend.Vault != nil:
+		if backend.Vault.KeyName == "" || backend.Vault.TokenSecretName == "" {
+			return errors.New("keyBackend.vault requires keyName and tokenSecretName")
+		}
+
+	case backend.PKCS11 != nil:
+		if backend.PKCS11.TokenLabel == "" || backend.PKCS11.KeyID == "" || backend.PKCS11.PinSecretName == "" {
+			return errors.New("keyBackend.pkcs11 requires tokenLabel, keyID and pinSecretName")
+		}
+
+	default:
+		return errors.New("keyBackend must set one of vault and pkcs11")
+	}
+
+	return nil
+}
+
+// DefaultKeyBackend returns the key backend configured for the operator, whose secrets are read from the target
+// project. Nil is returned when the default key is stored in a secret.
+func DefaultKeyBackend(configuration config.Config) *v1alpha2.KeyBackend {
+
+	switch configuration.KeyBackend {
+	case config.KeyBackendVault:
+		return &v1alpha2.KeyBackend{Vault: &v1alpha2.VaultTransitKeySource{
+			Address:         configuration.VaultAddress,
+			Mount:           configuration.VaultTransitMount,
+			KeyName:         configuration.VaultTransitKey,
+			TokenSecretName: configuration.VaultTokenSecret,
+		}}
+
+	case config.KeyBackendPKCS11:
+		return &v1alpha2.KeyBackend{PKCS11: &v1alpha2.PKCS11KeySource{
+			TokenLabel:    configuration.PKCS11TokenLabel,
+			KeyID:         configuration.PKCS11KeyID,
+			PinSecretName: configuration.PKCS11PinSecret,
+		}}
+	}
+
+	return nil
+}
+
+// SigningKeySecretPrefix returns the prefix of the names of the secrets holding the keys generated for a SigningKey
+func SigningKeySecretPrefix(signingKey *v1alpha2.SigningKey) string {
+	return fmt.Sprintf("signingkey-%s-", signingKey.UID)
+}
+
+// ValidateSigningKeySecret checks that the secret the current key of a SigningKey is stored in was generated for the
+// SigningKey. The status of a SigningKey could otherwise name any secret of the target project.
+func ValidateSigningKeySecret(signingKey *v1alpha2.SigningKey) error {
+
+	current := signingKey.Status.CurrentKey
+	if current == nil {
+		return fmt.Errorf("SigningKey '%s' has no key yet", signingKey.Name)
+	}
+
+	// Keys held by a key backend are never stored in a secret
+	if current.SecretName == "" && signingKey.Spec.KeyBackend != nil {
+		return nil
+	}
+
+	if !strings.HasPrefix(current.SecretName, SigningKeySecretPrefix(signingKey)) {
+		return fmt.Errorf("Secret '%s' of SigningKey '%s' was not generated for the SigningKey", current.SecretName, signingKey.Name)
+	}
+
+	return nil
+}
+
+// Identity splits signBy into the name and email of the identity a backend key is issued to
+func Identity(signBy string) (string, string) {
+	if strings.Contains(signBy, "@") {
+		return "", signBy
+	}
+
+	return signBy, ""
+}
+
+// secretValue returns a field of a secret
+func secretValue(c client.Client, name types.NamespacedName, key string) (string, error) {
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), name, secret); err != nil {
+		return "", fmt.Errorf("Error retrieving Secret '%s' in Namespace '%s': %v", name.Name, name.Namespace, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("Secret '%s' in Namespace '%s' has no '%s' field", name.Name, name.Namespace, key)
+	}
+
+	return strings.TrimSpace(string(value)), nil
+}
@@ -6,11 +6,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha1"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
 	"github.com/redhat-cop/image-security/pkg/controller/config"
 	"github.com/redhat-cop/image-security/pkg/controller/images"
 	"github.com/redhat-cop/image-security/pkg/controller/util"
 	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -19,63 +20,212 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func UpdateOnImageSigningCompletionError(client client.Client, message string, imageSigningRequest v1alpha1.ImageSigningRequest) error {
+const (
+	// defaultRetryBackoff is how long to wait before relaunching a failed signing pod when the retry policy sets none
+	defaultRetryBackoff = 10 * time.Second
+	// maxRetryBackoff caps the backoff between attempts, which doubles after every failure
+	maxRetryBackoff = 10 * time.Minute
+)
+
+func UpdateOnImageSigningCompletionError(client client.Client, message string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
 
-	condition := util.NewImageExecutionCondition(message, corev1.ConditionFalse, images.ImageExecutionConditionFinished)
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionFalse, images.ReasonSigningFailed, message)
 
-	imageSigningRequest.Status.EndTime = condition.LastTransitionTime
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonSigningFailed, message))
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
 
-	return updateImageSigningRequest(client, &imageSigningRequest, condition, images.PhaseFailed)
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseFailed, images.ReasonSigningFailed, message)
 }
 
-func UpdateOnImageSigningCompletionSuccess(client client.Client, message string, signedImage string, imageSigningRequest v1alpha1.ImageSigningRequest) error {
+func UpdateOnImageSigningCompletionSuccess(client client.Client, message string, signedImage string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
 
-	condition := util.NewImageExecutionCondition(message, corev1.ConditionTrue, images.ImageExecutionConditionFinished)
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionTrue, images.ReasonSigned, message)
 
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonSigned, message))
 	imageSigningRequest.Status.SignedImage = signedImage
-	imageSigningRequest.Status.EndTime = condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
 
-	return updateImageSigningRequest(client, &imageSigningRequest, condition, images.PhaseCompleted)
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseCompleted, images.ReasonSigned, message)
 }
 
-func UpdateOnImageSigningInitializationFailure(client client.Client, message string, imageSigningRequest v1alpha1.ImageSigningRequest) error {
+func UpdateOnImageSigningInitializationFailure(client client.Client, reason string, message string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
 
-	condition := util.NewImageExecutionCondition(message, corev1.ConditionFalse, images.ImageExecutionConditionInitialization)
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionInitialization, corev1.ConditionFalse, reason, message)
 
-	imageSigningRequest.Status.StartTime = condition.LastTransitionTime
-	imageSigningRequest.Status.EndTime = condition.LastTransitionTime
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonSigningNotStarted, message))
+	imageSigningRequest.Status.StartTime = &condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
 
-	return updateImageSigningRequest(client, &imageSigningRequest, condition, images.PhaseFailed)
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseFailed, reason, message)
 }
 
-func UpdateOnSigningPodLaunch(client client.Client, message string, unsignedImage string, imageSigningRequest v1alpha1.ImageSigningRequest) error {
+func UpdateOnSigningPodLaunch(client client.Client, message string, unsignedImage string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
 
-	condition := util.NewImageExecutionCondition(message, corev1.ConditionTrue, images.ImageExecutionConditionInitialization)
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionInitialization, corev1.ConditionTrue, images.ReasonSigningPodLaunched, message)
 
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionTrue, images.ReasonSigningInProgress, message))
 	imageSigningRequest.Status.UnsignedImage = unsignedImage
-	imageSigningRequest.Status.StartTime = condition.LastTransitionTime
-	imageSigningRequest.Status.EndTime = metav1.NewTime(time.Time{}).String()
+	imageSigningRequest.Status.StartTime = &condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = nil
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseRunning, images.ReasonSigningInProgress, message)
+}
+
+// UpdateOnDeadlineExceeded fails a request whose signing pods did not sign the image within its active deadline
+func UpdateOnDeadlineExceeded(client client.Client, message string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
+
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionFalse, images.ReasonDeadlineExceeded, message)
+
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonDeadlineExceeded, message))
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseFailed, images.ReasonDeadlineExceeded, message)
+}
+
+// UpdateOnSigningPodRetry records a failed signing pod that will be relaunched, which keeps the request Running
+func UpdateOnSigningPodRetry(client client.Client, message string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
+
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionTrue, images.ReasonSigningRetrying, message))
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseRunning, images.ReasonSigningRetrying, message)
+}
+
+// ActiveDeadlineSeconds returns how long the signing pods of a request may take, or zero when it has no deadline
+func ActiveDeadlineSeconds(instance *v1alpha2.ImageSigningRequest, configuration config.Config) int64 {
+	if instance.Spec.ActiveDeadlineSeconds != nil {
+		return *instance.Spec.ActiveDeadlineSeconds
+	}
+
+	return configuration.SigningActiveDeadlineSeconds
+}
 
-	return updateImageSigningRequest(client, &imageSigningRequest, condition, images.PhaseRunning)
+// Deadline returns when a request signed by signing pods fails if the image has not been signed. False is returned
+// when the request has no deadline or no pod has been launched yet.
+func Deadline(instance *v1alpha2.ImageSigningRequest, configuration config.Config) (time.Time, bool) {
+
+	seconds := ActiveDeadlineSeconds(instance, configuration)
+	if seconds <= 0 || instance.Status.StartTime == nil {
+		return time.Time{}, false
+	}
+
+	return instance.Status.StartTime.Add(time.Duration(seconds) * time.Second), true
 }
 
-func updateImageSigningRequest(client client.Client, imageSigningRequest *v1alpha1.ImageSigningRequest, condition images.ImageExecutionCondition, phase images.ImageExecutionPhase) error {
+// MaxAttempts returns the number of signing pods that may be launched for a request
+func MaxAttempts(instance *v1alpha2.ImageSigningRequest) int {
+	if instance.Spec.RetryPolicy == nil || instance.Spec.RetryPolicy.MaxAttempts < 1 {
+		return 1
+	}
+
+	return int(instance.Spec.RetryPolicy.MaxAttempts)
+}
+
+// RetryBackoff returns how long to wait after the given number of failed attempts before the signing pod of a
+// request is relaunched
+func RetryBackoff(instance *v1alpha2.ImageSigningRequest, failures int) time.Duration {
+
+	backoff := defaultRetryBackoff
+	if instance.Spec.RetryPolicy != nil && instance.Spec.RetryPolicy.Backoff != nil {
+		backoff = instance.Spec.RetryPolicy.Backoff.Duration
+	}
+
+	for i := 1; i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
+
+// UpdateOnInProcessSigningSuccess records an image signed within the operator, which starts and finishes in a single reconcile
+func UpdateOnInProcessSigningSuccess(client client.Client, message string, signedImage string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
+
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionInitialization, corev1.ConditionTrue, images.ReasonSigningInProcess, message)
 
-	imageSigningRequest.Status.Conditions = append(imageSigningRequest.Status.Conditions, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonSigned, message))
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionTrue, images.ReasonSigned, message))
+	imageSigningRequest.Status.UnsignedImage = signedImage
+	imageSigningRequest.Status.SignedImage = signedImage
+	imageSigningRequest.Status.StartTime = &condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseCompleted, images.ReasonSigned, message)
+}
+
+// UpdateOnAlreadySigned completes a request without signing, as the image already carries a signature made by the
+// key it would be signed with
+func UpdateOnAlreadySigned(client client.Client, message string, signedImage string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
+
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionInitialization, corev1.ConditionTrue, images.ReasonAlreadySigned, message)
+
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonAlreadySigned, message))
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionTrue, images.ReasonAlreadySigned, message))
+	imageSigningRequest.Status.SignedImage = signedImage
+	imageSigningRequest.Status.StartTime = &condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseCompleted, images.ReasonAlreadySigned, message)
+}
+
+// UpdateOnInProcessSigningFailure records a failure to sign an image within the operator
+func UpdateOnInProcessSigningFailure(client client.Client, message string, imageSigningRequest v1alpha2.ImageSigningRequest) error {
+
+	condition := util.NewImageSigningCondition(images.ImageExecutionConditionInitialization, corev1.ConditionTrue, images.ReasonSigningInProcess, message)
+
+	util.SetImageSigningCondition(&imageSigningRequest.Status, condition)
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionSigning, corev1.ConditionFalse, images.ReasonSigningFailed, message))
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionFinished, corev1.ConditionFalse, images.ReasonSigningFailed, message))
+	imageSigningRequest.Status.StartTime = &condition.LastTransitionTime
+	imageSigningRequest.Status.EndTime = &condition.LastTransitionTime
+
+	return updateImageSigningRequest(client, &imageSigningRequest, images.PhaseFailed, images.ReasonSigningFailed, message)
+}
+
+// updateImageSigningRequest moves the request to the given phase and derives the summary Ready condition from it
+func updateImageSigningRequest(client client.Client, imageSigningRequest *v1alpha2.ImageSigningRequest, phase images.ImageExecutionPhase, reason string, message string) error {
+
+	ready := corev1.ConditionFalse
+	if phase == images.PhaseCompleted {
+		ready = corev1.ConditionTrue
+	}
+
+	util.SetImageSigningCondition(&imageSigningRequest.Status, util.NewImageSigningCondition(images.ImageExecutionConditionReady, ready, reason, message))
 	imageSigningRequest.Status.Phase = phase
+	imageSigningRequest.Status.ObservedGeneration = imageSigningRequest.Generation
 
 	err := client.Status().Update(context.TODO(), imageSigningRequest)
 	return err
 }
 
-func LaunchSigningPod(client client.Client, scheme *runtime.Scheme, config config.Config, instance *v1alpha1.ImageSigningRequest, image string, imageDigest string, ownerID string, ownerReference string, gpgSecretName string, gpgSignBy string, pushSecret string) (string, error) {
+func LaunchSigningPod(client client.Client, scheme *runtime.Scheme, config config.Config, instance *v1alpha2.ImageSigningRequest, image string, resolvedImage string, imageDigest string, ownerID string, ownerReference string, gpgSecretName string, gpgSignBy string, pushSecret string) (string, error) {
 
-	pod, err := createSigningPod(scheme, instance, config.SignScanImage, config.TargetProject, image, imageDigest, ownerID, ownerReference, "imagemanager", gpgSecretName, gpgSignBy, pushSecret)
+	pod, err := createSigningPod(scheme, instance, config.SignScanImage, config.TargetProject, image, resolvedImage, imageDigest, ownerID, ownerReference, "imagemanager", gpgSecretName, gpgSignBy, pushSecret, config.SignatureStorage)
 	if err != nil {
 		logrus.Errorf("Error Generating Pod: %v'", err)
 		return "", err
 	}
 
+	// Pods relaunched after a failure only get what remains of the deadline of the request
+	if seconds := ActiveDeadlineSeconds(instance, config); seconds > 0 {
+		if deadline, ok := Deadline(instance, config); ok {
+			seconds = int64(time.Until(deadline).Seconds())
+			if seconds < 1 {
+				seconds = 1
+			}
+		}
+		pod.Spec.ActiveDeadlineSeconds = &seconds
+	}
+
 	err = client.Create(context.TODO(), pod)
 
 	if err != nil {
@@ -91,7 +241,26 @@ func LaunchSigningPod(client client.Client, scheme *runtime.Scheme, config confi
 	return key, nil
 }
 
-func createSigningPod(scheme *runtime.Scheme, instance *v1alpha1.ImageSigningRequest, signScanImage string, targetProject string, image string, imageDigest string, ownerID string, ownerReference string, serviceAccount string, gpgSecret string, signBy string, pushSecret string) (*corev1.Pod, error) {
+// KeySecretCopyName returns the name of the copy of the key secret of a request made in the target project for its
+// signing pods to mount
+func KeySecretCopyName(instance *v1alpha2.ImageSigningRequest) string {
+	return string(instance.UID)
+}
+
+// DeleteKeySecretCopy removes the copy of the key secret of a request from the target project. Owner references
+// cannot cross namespaces, so the copy is not garbage collected along with the request.
+func DeleteKeySecretCopy(c client.Client, configuration config.Config, instance *v1alpha2.ImageSigningRequest) error {
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: KeySecretCopyName(instance), Namespace: configuration.TargetProject}}
+
+	if err := c.Delete(context.TODO(), secret); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func createSigningPod(scheme *runtime.Scheme, instance *v1alpha2.ImageSigningRequest, signScanImage string, targetProject string, image string, resolvedImage string, imageDigest string, ownerID string, ownerReference string, serviceAccount string, gpgSecret string, signBy string, pushSecret string, signatureStorage string) (*corev1.Pod, error) {
 	priv := true
 	pod := &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -119,6 +288,10 @@ func createSigningPod(scheme *runtime.Scheme, instance *v1alpha1.ImageSigningReq
 						Name:  "IMAGE",
 						Value: image,
 					},
+					{
+						Name:  "PULL_IMAGE",
+						Value: resolvedImage,
+					},
 					{
 						Name:  "PUSH_TYPE",
 						Value: "podman",
@@ -139,6 +312,10 @@ func createSigningPod(scheme *runtime.Scheme, instance *v1alpha1.ImageSigningReq
 						Name:  "SECRET_NAMESPACE",
 						Value: instance.ObjectMeta.Namespace,
 					},
+					{
+						Name:  "SIGNATURE_STORAGE",
+						Value: signatureStorage,
+					},
 				},
 				SecurityContext: &corev1.SecurityContext{
 					Privileged: &priv,
@@ -169,7 +346,11 @@ func createSigningPod(scheme *runtime.Scheme, instance *v1alpha1.ImageSigningReq
 	// Begin Custom Logic to support signing
 	hostPathVal, hostPathBool := os.LookupEnv("HOST_PATH_MOUNT")
 
-	if hostPathBool && strings.EqualFold("true", hostPathVal) {
+	// Signatures that are uploaded or handed back to the operator only need the sigstore of the container, so any
+	// node can sign
+	hostPathStorage := signatureStorage == "" || signatureStorage == config.SignatureStorageHostPath
+
+	if hostPathStorage && hostPathBool && strings.EqualFold("true", hostPathVal) {
 
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
 			Name: "sigstore",
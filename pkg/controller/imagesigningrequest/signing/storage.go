@@ -0,0 +1,125 @@
+package signing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/redhat-cop/image-security/pkg/signature/storage"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// podSignature is the signature handed back by a signing pod through its termination log
+type podSignature struct {
+	Digest    string `json:"digest"`
+	Signature []byte `json:"signature"`
+}
+
+// SignatureStore returns the store that signatures made by the operator are written to, as selected by the
+// SIGNATURE_STORAGE setting. The pull secret in the namespace grants access to registries storing signatures.
+func SignatureStore(c client.Client, imageClient *imageset.ImageV1Client, configuration config.Config, namespace string, pullSecret string) (storage.Store, error) {
+
+	switch configuration.SignatureStorage {
+	case "", config.SignatureStorageHostPath, config.SignatureStorageLookaside:
+		return storage.NewLookaside("file://" + configuration.SigstoreDir)
+	case config.SignatureStorageImageSignature:
+		return storage.NewImageSignatures(imageClient), nil
+	case config.SignatureStorageRegistry:
+		credentials, err := RegistryCredentials(c, configuration, namespace, pullSecret)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewRegistryExtension(registry.NewClient(credentials, configuration.RegistryTLSVerify)), nil
+	default:
+		return nil, fmt.Errorf("Unknown signature storage '%s'", configuration.SignatureStorage)
+	}
+}
+
+// SignatureSource returns the store that signatures are read from to verify them. Signatures kept in the sigstore
+// directory of the nodes can only be read through the sigstore referenced by SIGSTORE_URL.
+func SignatureSource(c client.Client, imageClient *imageset.ImageV1Client, configuration config.Config, namespace string, pullSecret string) (storage.Store, error) {
+
+	switch configuration.SignatureStorage {
+	case "", config.SignatureStorageHostPath, config.SignatureStorageLookaside:
+		if configuration.SigstoreURL != "" {
+			return storage.NewLookaside(configuration.SigstoreURL)
+		}
+		if configuration.SignatureStorage != config.SignatureStorageLookaside {
+			return nil, errors.New("No sigstore is configured to read signatures from")
+		}
+	}
+
+	return SignatureStore(c, imageClient, configuration, namespace, pullSecret)
+}
+
+// SignatureFromPod reports whether signing pods hand their signature back to the operator to be stored, rather
+// than storing it themselves
+func SignatureFromPod(configuration config.Config) bool {
+	return configuration.SignatureStorage == config.SignatureStorageLookaside || configuration.SignatureStorage == config.SignatureStorageRegistry
+}
+
+// StorePodSignature writes the signature that sign-image wrote to the termination log of a signing pod to the
+// store. The signature must claim the manifest digest the requested image was resolved to, so a pod cannot store
+// a signature for any other image.
+func StorePodSignature(store storage.Store, image string, resolvedImage string, terminationMessage string) error {
+
+	signed := &podSignature{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(terminationMessage)), signed); err != nil {
+		return fmt.Errorf("Error reading signature from termination log: %v", err)
+	}
+
+	manifestDigest, err := digest.Parse(signed.Digest)
+	if err != nil {
+		return fmt.Errorf("Invalid digest '%s' in termination log: %v", signed.Digest, err)
+	}
+
+	if len(signed.Signature) == 0 {
+		return fmt.Errorf("No signature found in termination log")
+	}
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := registry.ParseReference(resolvedImage)
+	if err != nil {
+		return err
+	}
+
+	resolvedDigest, ok := resolved.(reference.Digested)
+	if !ok {
+		return fmt.Errorf("Resolved image '%s' has no digest", resolvedImage)
+	}
+
+	if manifestDigest != resolvedDigest.Digest() {
+		return fmt.Errorf("Termination log is for digest '%s' rather than the resolved digest '%s'", manifestDigest, resolvedDigest.Digest())
+	}
+
+	payload, err := signature.UnverifiedPayload(signed.Signature)
+	if err != nil {
+		return err
+	}
+
+	if payload.Critical.Image.DockerManifestDigest != resolvedDigest.Digest().String() {
+		return fmt.Errorf("Signature is for digest '%s' rather than the resolved digest '%s'", payload.Critical.Image.DockerManifestDigest, resolvedDigest.Digest())
+	}
+
+	signedAs, err := reference.ParseNormalizedNamed(payload.Critical.Identity.DockerReference)
+	if err != nil {
+		return fmt.Errorf("Invalid identity '%s' in signature: %v", payload.Critical.Identity.DockerReference, err)
+	}
+
+	if signedAs.Name() != ref.Name() {
+		return fmt.Errorf("Signature is for repository '%s' rather than '%s'", signedAs.Name(), ref.Name())
+	}
+
+	return store.Put(ref, manifestDigest, signed.Signature)
+}
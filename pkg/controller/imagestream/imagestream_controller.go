@@ -0,0 +1,247 @@
+package imagestream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/util"
+	"github.com/sirupsen/logrus"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ImageStreamLabel is set on the ImageSigningRequests created for an ImageStream
+const ImageStreamLabel = "cop.redhat.com/imagestream"
+
+// digestNameLength is the number of hex characters of the image digest used in the name of a request
+const digestNameLength = 16
+
+var log = logf.Log.WithName("controller_imagestream")
+
+// Add creates a new ImageStream Controller and adds it to the Manager
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	client, err := imageset.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil
+	}
+	return &ReconcileImageStream{client: mgr.GetClient(), scheme: mgr.GetScheme(), imageClient: client}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("imagestream-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ImageStreams, new images are recorded in their status
+	err = c.Watch(&source.Kind{Type: &imagev1.ImageStream{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileImageStream implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileImageStream{}
+
+// ReconcileImageStream signs the images tagged into ImageStreams that are opted in to automatic signing
+type ReconcileImageStream struct {
+	client      client.Client
+	scheme      *runtime.Scheme
+	imageClient *imageset.ImageV1Client
+}
+
+// Reconcile creates an ImageSigningRequest for the latest image of every tag opted in to automatic signing. Requests
+// are named after the digest of the image, so an image tagged more than once is signed once. The requests are owned
+// by the ImageStream and removed along with it. The images requests were created for are recorded on the stream, so
+// requests removed once finished are not created again.
+func (r *ReconcileImageStream) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+
+	stream, err := r.imageClient.ImageStreams(request.Namespace).Get(request.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if stream.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	requested := autoSignedImages(stream)
+	signed := map[string]string{}
+
+	for _, tag := range stream.Status.Tags {
+
+		enabled, signingKey := autoSignSettings(stream, tag.Tag)
+		if !enabled {
+			continue
+		}
+
+		event := util.LatestTaggedImage(stream, tag.Tag)
+		if event == nil || event.Image == "" {
+			continue
+		}
+
+		signed[tag.Tag] = event.Image
+
+		if requested[tag.Tag] == event.Image {
+			continue
+		}
+
+		created, err := r.createSigningRequest(stream, event.Image, signingKey)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if created {
+			logrus.Infof("Requested Signing of Image '%s' Tagged '%s:%s' in Namespace '%s'", event.Image, stream.Name, tag.Tag, stream.Namespace)
+		}
+	}
+
+	if reflect.DeepEqual(requested, signed) {
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.recordAutoSignedImages(stream, signed)
+}
+
+// autoSignedImages returns the latest image of each tag of a stream a request was created for
+func autoSignedImages(stream *imagev1.ImageStream) map[string]string {
+
+	requested := map[string]string{}
+
+	if value, ok := stream.Annotations[common.AutoSignedImagesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &requested); err != nil {
+			logrus.Warnf("Ignoring Invalid '%s' Annotation of ImageStream '%s' in Namespace '%s': %v", common.AutoSignedImagesAnnotation, stream.Name, stream.Namespace, err)
+			return map[string]string{}
+		}
+	}
+
+	return requested
+}
+
+// recordAutoSignedImages records the latest image of each tag of a stream a request was created for
+func (r *ReconcileImageStream) recordAutoSignedImages(stream *imagev1.ImageStream, signed map[string]string) error {
+
+	value, err := json.Marshal(signed)
+	if err != nil {
+		return err
+	}
+
+	if stream.Annotations == nil {
+		stream.Annotations = map[string]string{}
+	}
+	stream.Annotations[common.AutoSignedImagesAnnotation] = string(value)
+
+	_, err = r.imageClient.ImageStreams(stream.Namespace).Update(stream)
+
+	return err
+}
+
+// createSigningRequest creates the ImageSigningRequest for an image of a stream unless it already exists
+func (r *ReconcileImageStream) createSigningRequest(stream *imagev1.ImageStream, image string, signingKey string) (bool, error) {
+
+	imageSigningRequest := &v1alpha2.ImageSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      signingRequestName(stream.Name, image),
+			Namespace: stream.Namespace,
+			Labels: map[string]string{
+				ImageStreamLabel: stream.Name,
+			},
+		},
+		Spec: v1alpha2.ImageSigningRequestSpec{
+			ContainerImage: &kapi.ObjectReference{
+				Kind: "ImageStreamImage",
+				Name: fmt.Sprintf("%s@%s", stream.Name, image),
+			},
+		},
+	}
+
+	if signingKey != "" {
+		imageSigningRequest.Spec.SigningKeyRef = &kapi.LocalObjectReference{Name: signingKey}
+	}
+
+	if err := controllerutil.SetControllerReference(stream, imageSigningRequest, r.scheme); err != nil {
+		return false, err
+	}
+
+	err := r.client.Create(context.TODO(), imageSigningRequest)
+	if errors.IsAlreadyExists(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// autoSignSettings returns whether a tag of a stream is signed automatically and the SigningKey signing it. Tag
+// annotations take precedence over those of the stream, so a tag annotated with "false" is left out of a stream
+// that is opted in.
+func autoSignSettings(stream *imagev1.ImageStream, tag string) (bool, string) {
+
+	enabled := stream.Annotations[common.AutoSignAnnotation] == "true"
+	signingKey := stream.Annotations[common.AutoSignKeyAnnotation]
+
+	for _, tagReference := range stream.Spec.Tags {
+		if tagReference.Name != tag {
+			continue
+		}
+
+		if value, ok := tagReference.Annotations[common.AutoSignAnnotation]; ok {
+			enabled = value == "true"
+		}
+
+		if value, ok := tagReference.Annotations[common.AutoSignKeyAnnotation]; ok {
+			signingKey = value
+		}
+	}
+
+	return enabled, signingKey
+}
+
+// signingRequestName names the request for an image of a stream after the stream and the digest of the image
+func signingRequestName(streamName string, image string) string {
+
+	digest := image
+	if index := strings.Index(image, ":"); index != -1 {
+		digest = image[index+1:]
+	}
+
+	if len(digest) > digestNameLength {
+		digest = digest[:digestNameLength]
+	}
+
+	// Names are limited to 253 characters
+	maxStreamName := 253 - len(digest) - 1
+	if len(streamName) > maxStreamName {
+		streamName = streamName[:maxStreamName]
+	}
+
+	return fmt.Sprintf("%s-%s", streamName, digest)
+}
@@ -3,11 +3,15 @@ package pod
 import (
 	"context"
 	"fmt"
+	"time"
 
 	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
-	imagesigningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha1"
+	imagescanningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagescanningrequests/v1alpha1"
+	imagesigningrequestsv1alpha2 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
 	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
 	"github.com/redhat-cop/image-security/pkg/controller/images"
+	"github.com/redhat-cop/image-security/pkg/controller/imagescanningrequest/scanning"
 	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -44,7 +49,12 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	if err != nil {
 		return nil
 	}
-	return &ReconcilePod{client: mgr.GetClient(), scheme: mgr.GetScheme(), imageClient: client}
+	// Pod logs cannot be read through the client of the manager
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil
+	}
+	return &ReconcilePod{client: mgr.GetClient(), scheme: mgr.GetScheme(), config: config.LoadConfig(), imageClient: client, kubeClient: kubeClient}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -78,7 +88,9 @@ type ReconcilePod struct {
 	// that reads objects from the cache and writes to the apiserver
 	client      client.Client
 	scheme      *runtime.Scheme
+	config      config.Config
 	imageClient *imageset.ImageV1Client
+	kubeClient  kubernetes.Interface
 }
 
 // Reconcile reads that state of the cluster for a Pod object and makes changes based on the state read
@@ -112,14 +124,26 @@ func (r *ReconcilePod) Reconcile(request reconcile.Request) (reconcile.Result, e
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling Pod")
 
+	switch pod.Annotations[common.CopTypeAnnotation] {
+	case common.ImageSigningTypeAnnotation:
+		return r.reconcileSigningPod(pod)
+	case common.ImageScanningTypeAnnotation:
+		return r.reconcileScanningPod(pod)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcilePod) reconcileSigningPod(pod *corev1.Pod) (reconcile.Result, error) {
+
 	podOwnerAnnotation := pod.Annotations[common.CopOwnerAnnotation]
 	podMetadataKey, _ := cache.MetaNamespaceKeyFunc(pod)
 	isrNamespace, isrName, err := cache.SplitMetaNamespaceKey(podOwnerAnnotation)
 
-	imageSigningRequest := &imagesigningrequestsv1alpha1.ImageSigningRequest{
+	imageSigningRequest := &imagesigningrequestsv1alpha2.ImageSigningRequest{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ImageSigningRequest",
-			APIVersion: "cop.redhat.com/v1alpha2",
+			APIVersion: imagesigningrequestsv1alpha2.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      isrName,
@@ -135,6 +159,10 @@ func (r *ReconcilePod) Reconcile(request reconcile.Request) (reconcile.Result, e
 
 	// Check if ImageSigningRequest has already been marked as Succeeded or Failed
 	if imageSigningRequest.Status.Phase == images.PhaseCompleted || imageSigningRequest.Status.Phase == images.PhaseFailed {
+		// The key secret copy is left behind when deleting it failed as the request finished
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, imageSigningRequest)
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -145,26 +173,260 @@ func (r *ReconcilePod) Reconcile(request reconcile.Request) (reconcile.Result, e
 
 	// Check if Failed
 	if pod.Status.Phase == corev1.PodFailed {
-		logrus.Infof("Signing Pod Failed. Updating ImageSiginingRequest %s", podOwnerAnnotation)
+		return r.reconcileFailedSigningPod(pod, imageSigningRequest)
+
+	} else if pod.Status.Phase == corev1.PodSucceeded {
+
+		dockerImageID := imageSigningRequest.Status.UnsignedImage
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// Signatures handed back by the pod are stored by the operator
+		if signing.SignatureFromPod(r.config) {
+			err = r.storePodSignature(pod, imageSigningRequest)
 
-		err = signing.UpdateOnImageSigningCompletionError(r.client, fmt.Sprintf("Signing Pod Failed '%v'", err), *imageSigningRequest)
+			if err != nil {
+				logrus.Errorf("Error Storing Signature of Pod '%s': %v", podMetadataKey, err)
+
+				err = signing.UpdateOnImageSigningCompletionError(r.client, fmt.Sprintf("Error Storing Signature '%v'", err), *imageSigningRequest)
+
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+
+				return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, imageSigningRequest)
+			}
+		}
+
+		logrus.Infof("Signing Pod Succeeded. Updating ImageSiginingRequest %s", pod.Annotations[common.CopOwnerAnnotation])
+
+		err = signing.UpdateOnImageSigningCompletionSuccess(r.client, "Image Signed", dockerImageID, *imageSigningRequest)
 
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
+		return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, imageSigningRequest)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileFailedSigningPod records the failed attempt of a signing pod and relaunches the pod once the backoff of the
+// retry policy of the request has elapsed. The request only fails once every attempt has been used.
+func (r *ReconcilePod) reconcileFailedSigningPod(pod *corev1.Pod, imageSigningRequest *imagesigningrequestsv1alpha2.ImageSigningRequest) (reconcile.Result, error) {
+
+	podMetadataKey, _ := cache.MetaNamespaceKeyFunc(pod)
+	status := &imageSigningRequest.Status
+
+	// Requests launched before attempts were recorded have made a single attempt
+	if len(status.Attempts) == 0 {
+		status.Attempts = append(status.Attempts, imagesigningrequestsv1alpha2.ImageSigningAttempt{Pod: podMetadataKey, StartTime: status.StartTime})
+	}
+
+	attempt := &status.Attempts[len(status.Attempts)-1]
+
+	// The pods of earlier attempts have already been retried
+	if attempt.Pod != podMetadataKey {
 		return reconcile.Result{}, nil
+	}
 
-	} else if pod.Status.Phase == corev1.PodSucceeded {
+	if attempt.EndTime == nil {
+		recordPodFailure(attempt, pod)
+	}
+
+	if status.Failure == nil || status.Failure.Pod != podMetadataKey {
+		status.Failure = signing.PodFailure(pod, r.podLogTail(pod))
+	}
+
+	failureMessage := signing.FailureMessage(status.Failure)
+
+	// Pods stopped by their deadline have used all of the time of the request, so they are not retried
+	deadline, hasDeadline := signing.Deadline(imageSigningRequest, r.config)
+	if pod.Status.Reason == images.ReasonDeadlineExceeded || (hasDeadline && !time.Now().Before(deadline)) {
+		message := fmt.Sprintf("Image Not Signed Within the Deadline of %d Seconds. %s", signing.ActiveDeadlineSeconds(imageSigningRequest, r.config), failureMessage)
+		logrus.Warnf(message)
+
+		if err := signing.UpdateOnDeadlineExceeded(r.client, message, *imageSigningRequest); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, imageSigningRequest)
+	}
+
+	failures := len(status.Attempts)
+	maxAttempts := signing.MaxAttempts(imageSigningRequest)
+
+	if failures >= maxAttempts {
+		logrus.Infof("Signing Pod Failed. Updating ImageSiginingRequest %s", pod.Annotations[common.CopOwnerAnnotation])
+
+		message := failureMessage
+		if failures > 1 {
+			message = fmt.Sprintf("All %d Attempts Failed. %s", failures, failureMessage)
+		}
+
+		if err := signing.UpdateOnImageSigningCompletionError(r.client, message, *imageSigningRequest); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// No further attempt will mount the key secret copy
+		return reconcile.Result{}, signing.DeleteKeySecretCopy(r.client, r.config, imageSigningRequest)
+	}
+
+	retryAt := attempt.EndTime.Add(signing.RetryBackoff(imageSigningRequest, failures))
+
+	if wait := time.Until(retryAt); wait > 0 {
+		message := fmt.Sprintf("%s. Attempt %d of %d Starts at %s", failureMessage, failures+1, maxAttempts, retryAt.UTC().Format(time.RFC3339))
+		logrus.Infof(message)
+
+		if err := signing.UpdateOnSigningPodRetry(r.client, message, *imageSigningRequest); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	signingPodName, err := r.relaunchSigningPod(pod, imageSigningRequest, failures+1)
+	if err != nil {
+		logrus.Errorf("Error Relaunching Signing Pod for ImageSigningRequest '%s': %v", pod.Annotations[common.CopOwnerAnnotation], err)
+		return reconcile.Result{}, err
+	}
+
+	now := metav1.Now()
+	status.Attempts = append(status.Attempts, imagesigningrequestsv1alpha2.ImageSigningAttempt{Pod: signingPodName, StartTime: &now})
+
+	message := fmt.Sprintf("Signing Pod Relaunched '%s', Attempt %d of %d", signingPodName, failures+1, maxAttempts)
+	logrus.Infof(message)
+
+	return reconcile.Result{}, signing.UpdateOnSigningPodRetry(r.client, message, *imageSigningRequest)
+}
+
+// relaunchSigningPod launches a fresh signing pod for another attempt of a request, signing the same image with the
+// same key as the failed pod
+func (r *ReconcilePod) relaunchSigningPod(failed *corev1.Pod, imageSigningRequest *imagesigningrequestsv1alpha2.ImageSigningRequest, attempt int) (string, error) {
+
+	gpgSecretName := ""
+	for _, volume := range failed.Spec.Volumes {
+		if volume.Name == "gpg" && volume.Secret != nil {
+			gpgSecretName = volume.Secret.SecretName
+		}
+	}
+
+	name := fmt.Sprintf("%s-%d", imageSigningRequest.UID, attempt)
+
+	signingPodName, err := signing.LaunchSigningPod(r.client, r.scheme, r.config, imageSigningRequest, podEnvValue(failed, "IMAGE"), podEnvValue(failed, "PULL_IMAGE"), podEnvValue(failed, "DIGEST"), name, failed.Annotations[common.CopOwnerAnnotation], gpgSecretName, podEnvValue(failed, "SIGNBY"), podEnvValue(failed, "SECRET"))
+
+	// The pod may have been launched by an earlier reconcile whose status update was lost
+	if errors.IsAlreadyExists(err) {
+		return fmt.Sprintf("%s/%s", failed.Namespace, name), nil
+	}
+
+	return signingPodName, err
+}
+
+// recordPodFailure records the exit code of the signing container of a failed pod and why it failed in its attempt
+func recordPodFailure(attempt *imagesigningrequestsv1alpha2.ImageSigningAttempt, pod *corev1.Pod) {
+
+	now := metav1.Now()
+	attempt.EndTime = &now
+	attempt.Reason = pod.Status.Reason
+
+	if len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return
+	}
+
+	terminated := pod.Status.ContainerStatuses[0].State.Terminated
+	attempt.ExitCode = terminated.ExitCode
+
+	if terminated.Reason != "" {
+		attempt.Reason = terminated.Reason
+	}
+
+	if !terminated.FinishedAt.IsZero() {
+		finishedAt := terminated.FinishedAt
+		attempt.EndTime = &finishedAt
+	}
+}
+
+// podLogTail returns the last lines of the log of the signing container of a pod. Nothing is returned when the log
+// cannot be read, such as for containers that never started.
+func (r *ReconcilePod) podLogTail(pod *corev1.Pod) string {
+
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+
+	tailLines := int64(signing.FailureLogLines)
+	logs, err := r.kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: pod.Spec.Containers[0].Name, TailLines: &tailLines}).DoRaw()
+	if err != nil {
+		logrus.Infof("Unable to Read Log of Signing Pod '%s/%s': %v", pod.Namespace, pod.Name, err)
+		return ""
+	}
+
+	return string(logs)
+}
+
+// storePodSignature writes the signature in the termination log of a signing pod to the signature store, provided it
+// is for the image the request resolved
+func (r *ReconcilePod) storePodSignature(pod *corev1.Pod, imageSigningRequest *imagesigningrequestsv1alpha2.ImageSigningRequest) error {
+
+	if len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].State.Terminated == nil {
+		return fmt.Errorf("Signing Pod has not terminated")
+	}
+
+	store, err := signing.SignatureStore(r.client, r.imageClient, r.config, podEnvValue(pod, "SECRET_NAMESPACE"), podEnvValue(pod, "SECRET"))
+	if err != nil {
+		return err
+	}
+
+	return signing.StorePodSignature(store, podEnvValue(pod, "IMAGE"), imageSigningRequest.Status.ResolvedImage, pod.Status.ContainerStatuses[0].State.Terminated.Message)
+}
+
+func (r *ReconcilePod) reconcileScanningPod(pod *corev1.Pod) (reconcile.Result, error) {
+
+	podOwnerAnnotation := pod.Annotations[common.CopOwnerAnnotation]
+	podMetadataKey, _ := cache.MetaNamespaceKeyFunc(pod)
+	isrNamespace, isrName, err := cache.SplitMetaNamespaceKey(podOwnerAnnotation)
+	if err != nil {
+		return reconcile.Result{}, nil
+	}
+
+	imageScanningRequest := &imagescanningrequestsv1alpha1.ImageScanningRequest{}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: isrName, Namespace: isrNamespace}, imageScanningRequest)
+	if err != nil {
+		logrus.Warnf("Could not find ImageScanningRequest '%s' from pod '%s'", podOwnerAnnotation, podMetadataKey)
+		return reconcile.Result{}, nil
+	}
+
+	// Only requests that are in phase Running are waiting on this pod
+	if imageScanningRequest.Status.Phase != images.PhaseRunning {
+		return reconcile.Result{}, nil
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		logrus.Infof("Scanning Pod Failed. Updating ImageScanningRequest %s", podOwnerAnnotation)
+
+		err = scanning.UpdateOnImageScanningCompletionError(r.client, fmt.Sprintf("Scanning Pod '%s' Failed", podMetadataKey), *imageScanningRequest)
 
-		dockerImageID := imageSigningRequest.Status.UnsignedImage
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
-		logrus.Infof("Signing Pod Succeeded. Updating ImageSiginingRequest %s", pod.Annotations[common.CopOwnerAnnotation])
+	} else if pod.Status.Phase == corev1.PodSucceeded {
 
-		err = signing.UpdateOnImageSigningCompletionSuccess(r.client, "Image Signed", dockerImageID, *imageSigningRequest)
+		var results *imagescanningrequestsv1alpha1.ImageScanningResults
+		if len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].State.Terminated != nil {
+			results, err = scanning.ParseScanningResults(pod.Status.ContainerStatuses[0].State.Terminated.Message)
+			if err != nil {
+				logrus.Warnf("Unable to parse scanning results from pod '%s': %v", podMetadataKey, err)
+			}
+		}
+
+		logrus.Infof("Scanning Pod Succeeded. Updating ImageScanningRequest %s", podOwnerAnnotation)
+
+		err = scanning.UpdateOnImageScanningCompletionSuccess(r.client, "Image Scanned", podEnvValue(pod, "DIGEST"), results, *imageScanningRequest)
 
 		if err != nil {
 			return reconcile.Result{}, err
@@ -173,3 +435,18 @@ func (r *ReconcilePod) Reconcile(request reconcile.Request) (reconcile.Result, e
 
 	return reconcile.Result{}, nil
 }
+
+// podEnvValue returns the value of an environment variable set on the first container of a pod
+func podEnvValue(pod *corev1.Pod, name string) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+
+	for _, env := range pod.Spec.Containers[0].Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+
+	return ""
+}
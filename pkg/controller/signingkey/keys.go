@@ -0,0 +1,128 @@
+package signingkey
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	algorithmRSA   = "RSA"
+	defaultKeySize = 3072
+)
+
+// generatedKey is a freshly generated key pair along with the secret data holding it
+type generatedKey struct {
+	fingerprint string
+	publicKey   string
+	data        map[string][]byte
+}
+
+// generateKey creates an OpenPGP key pair for the identity of the SigningKey. The keyrings are stored using the
+// same keys as a manually created GPG secret so they can be mounted into signing pods unchanged.
+func generateKey(spec v1alpha2.SigningKeySpec, now time.Time) (*generatedKey, error) {
+
+	if spec.Algorithm != "" && !strings.EqualFold(spec.Algorithm, algorithmRSA) {
+		return nil, fmt.Errorf("Unsupported key algorithm '%s'", spec.Algorithm)
+	}
+
+	bits := spec.KeySize
+	if bits == 0 {
+		bits = defaultKeySize
+	}
+
+	if bits < 2048 {
+		return nil, fmt.Errorf("Key size %d is too small, at least 2048 bits are required", bits)
+	}
+
+	config := &packet.Config{
+		DefaultHash: crypto.SHA256,
+		RSABits:     bits,
+		Time:        func() time.Time { return now },
+	}
+
+	entity, err := openpgp.NewEntity(spec.Name, spec.Comment, spec.Email, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Expiry != nil {
+		lifetime := uint32(spec.Expiry.Duration.Seconds())
+		for _, identity := range entity.Identities {
+			identity.SelfSignature.KeyLifetimeSecs = &lifetime
+		}
+		for _, subkey := range entity.Subkeys {
+			subkey.Sig.KeyLifetimeSecs = &lifetime
+		}
+	}
+
+	// Serializing the private key signs the identities and subkeys, so it must happen before the public key is written
+	secring := &bytes.Buffer{}
+	if err := entity.SerializePrivate(secring, config); err != nil {
+		return nil, err
+	}
+
+	pubring := &bytes.Buffer{}
+	if err := entity.Serialize(pubring); err != nil {
+		return nil, err
+	}
+
+	armored := &bytes.Buffer{}
+	writer, err := armor.Encode(armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(pubring.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return &generatedKey{
+		fingerprint: fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint),
+		publicKey:   armored.String(),
+		data: map[string][]byte{
+			common.GpgPublicKeyringKey: pubring.Bytes(),
+			common.GpgSecretKeyringKey: secring.Bytes(),
+			common.GpgPublicKeyKey:     armored.Bytes(),
+		},
+	}, nil
+}
+
+// backendKey reads the key held by the key backend of a SigningKey, wrapped in an OpenPGP key issued to the identity
+// of the SigningKey. No secret is created as the private key never leaves the backend.
+func (r *ReconcileSigningKey) backendKey(instance *v1alpha2.SigningKey) (*v1alpha2.SigningKeyVersion, error) {
+
+	key, err := signing.BackendKey(r.client, r.config, *instance.Spec.KeyBackend, instance.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := signature.NewEntity(key, instance.Spec.Name, instance.Spec.Comment, instance.Spec.Email, key.CreationTime())
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := signature.ArmoredPublicKey(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1alpha2.SigningKeyVersion{
+		Fingerprint:  fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint),
+		PublicKey:    publicKey,
+		CreationTime: metav1.NewTime(key.CreationTime()),
+	}, nil
+}
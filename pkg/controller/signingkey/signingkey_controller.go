@@ -0,0 +1,318 @@
+package signingkey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// signingKeyFinalizer allows the key secrets in the target project to be removed along with the SigningKey
+const signingKeyFinalizer = "imagesigningrequests.cop.redhat.com/signingkey"
+
+const defaultRetainedKeys = 3
+
+// keyBackendRetryInterval is how long to wait before reading a key from a key backend that could not be reached
+const keyBackendRetryInterval = time.Minute
+
+var log = logf.Log.WithName("controller_signingkey")
+
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileSigningKey{client: mgr.GetClient(), scheme: mgr.GetScheme(), config: config.LoadConfig()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("signingkey-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to SigningKey
+	err = c.Watch(&source.Kind{Type: &v1alpha2.SigningKey{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileSigningKey implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSigningKey{}
+
+// ReconcileSigningKey reconciles a SigningKey object
+type ReconcileSigningKey struct {
+	client client.Client
+	scheme *runtime.Scheme
+	config config.Config
+}
+
+// Reconcile generates a key for a new SigningKey and replaces it once the rotation period has elapsed or the key
+// has expired. Key pairs are stored in secrets in the target project so they are never readable from the namespace
+// of the SigningKey. Retired keys are kept until more than the retained number of keys have been generated.
+func (r *ReconcileSigningKey) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling SigningKey")
+
+	instance := &v1alpha2.SigningKey{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if instance.DeletionTimestamp != nil {
+		if !hasFinalizer(instance) {
+			return reconcile.Result{}, nil
+		}
+
+		versions := instance.Status.PreviousKeys
+		if instance.Status.CurrentKey != nil {
+			versions = append(versions, *instance.Status.CurrentKey)
+		}
+
+		for _, version := range versions {
+			if err := r.deleteKeySecret(instance, version.SecretName); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
+		removeFinalizer(instance)
+		return reconcile.Result{}, r.client.Update(context.TODO(), instance)
+	}
+
+	if !hasFinalizer(instance) {
+		instance.Finalizers = append(instance.Finalizers, signingKeyFinalizer)
+		return reconcile.Result{}, r.client.Update(context.TODO(), instance)
+	}
+
+	now := time.Now()
+
+	if rotationDue(instance, now) {
+
+		version, err := r.createKey(instance, now)
+
+		if err != nil {
+			errorMessage := fmt.Sprintf("Error Occurred Generating Key '%v'", err)
+			logrus.Errorf(errorMessage)
+
+			instance.Status.Message = errorMessage
+
+			// Key backends may be unavailable for a while, so reading their key is retried. The generation is left
+			// unobserved so that the retry reads the key again.
+			if instance.Spec.KeyBackend != nil {
+				return reconcile.Result{RequeueAfter: keyBackendRetryInterval}, r.client.Status().Update(context.TODO(), instance)
+			}
+
+			instance.Status.ObservedGeneration = instance.Generation
+
+			// An invalid spec will not succeed until it is changed, which triggers a new reconcile
+			return reconcile.Result{}, r.client.Status().Update(context.TODO(), instance)
+		}
+
+		logrus.Infof("Generated Key '%s' for SigningKey '%s/%s'", version.Fingerprint, instance.Namespace, instance.Name)
+
+		// A changed key backend spec may still hold the same key
+		if instance.Status.CurrentKey != nil && instance.Status.CurrentKey.Fingerprint != version.Fingerprint {
+			instance.Status.PreviousKeys = append([]v1alpha2.SigningKeyVersion{*instance.Status.CurrentKey}, instance.Status.PreviousKeys...)
+		}
+		instance.Status.CurrentKey = version
+
+		retainedKeys := defaultRetainedKeys
+		if instance.Spec.RetainedKeys != nil {
+			retainedKeys = *instance.Spec.RetainedKeys
+		}
+
+		for len(instance.Status.PreviousKeys) > retainedKeys {
+			retired := instance.Status.PreviousKeys[len(instance.Status.PreviousKeys)-1]
+			if err := r.deleteKeySecret(instance, retired.SecretName); err != nil {
+				return reconcile.Result{}, err
+			}
+			instance.Status.PreviousKeys = instance.Status.PreviousKeys[:len(instance.Status.PreviousKeys)-1]
+		}
+
+		instance.Status.Message = ""
+	}
+
+	instance.Status.NextRotationTime = nextRotationTime(instance)
+	instance.Status.ObservedGeneration = instance.Generation
+
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if instance.Status.NextRotationTime != nil {
+		return reconcile.Result{RequeueAfter: instance.Status.NextRotationTime.Sub(now)}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileSigningKey) createKey(instance *v1alpha2.SigningKey, now time.Time) (*v1alpha2.SigningKeyVersion, error) {
+
+	if instance.Spec.KeyBackend != nil {
+		return r.backendKey(instance)
+	}
+
+	key, err := generateKey(instance.Spec, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerReference, _ := cache.MetaNamespaceKeyFunc(instance)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        signing.SigningKeySecretPrefix(instance) + strings.ToLower(key.fingerprint[len(key.fingerprint)-16:]),
+			Namespace:   r.config.TargetProject,
+			Labels:      map[string]string{"type": common.SigningKeyTypeAnnotation},
+			Annotations: map[string]string{common.CopOwnerAnnotation: ownerReference, common.CopTypeAnnotation: common.SigningKeyTypeAnnotation},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: key.data,
+	}
+
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		return nil, err
+	}
+
+	version := &v1alpha2.SigningKeyVersion{
+		Fingerprint:  key.fingerprint,
+		PublicKey:    key.publicKey,
+		SecretName:   secret.Name,
+		CreationTime: metav1.NewTime(now),
+	}
+
+	if instance.Spec.Expiry != nil {
+		expirationTime := metav1.NewTime(now.Add(instance.Spec.Expiry.Duration))
+		version.ExpirationTime = &expirationTime
+	}
+
+	return version, nil
+}
+
+func (r *ReconcileSigningKey) deleteKeySecret(instance *v1alpha2.SigningKey, name string) error {
+
+	// Keys held by a key backend have no secret
+	if name == "" {
+		return nil
+	}
+
+	// Only secrets generated for the SigningKey are deleted, whatever its status names
+	if !strings.HasPrefix(name, signing.SigningKeySecretPrefix(instance)) {
+		logrus.Warnf("Not Deleting Secret '%s' as It Was Not Generated for SigningKey '%s/%s'", name, instance.Namespace, instance.Name)
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.config.TargetProject}, secret)
+
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Deleting Key Secret '%s' in Project '%s'", name, r.config.TargetProject)
+
+	err = r.client.Delete(context.TODO(), secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// rotationDue reports whether a new key must be generated, either because none exists yet, the rotation period
+// has elapsed or the current key has expired
+func rotationDue(instance *v1alpha2.SigningKey, now time.Time) bool {
+	current := instance.Status.CurrentKey
+
+	if current == nil {
+		return true
+	}
+
+	// Keys held by a key backend are never rotated, they are only read again when the spec changes
+	if instance.Spec.KeyBackend != nil {
+		return instance.Status.ObservedGeneration != instance.Generation
+	}
+
+	if next := nextRotationTime(instance); next != nil && !now.Before(next.Time) {
+		return true
+	}
+
+	return current.ExpirationTime != nil && !now.Before(current.ExpirationTime.Time)
+}
+
+func nextRotationTime(instance *v1alpha2.SigningKey) *metav1.Time {
+	current := instance.Status.CurrentKey
+
+	if current == nil || instance.Spec.KeyBackend != nil {
+		return nil
+	}
+
+	var next *metav1.Time
+
+	if instance.Spec.RotationPeriod != nil {
+		rotation := metav1.NewTime(current.CreationTime.Add(instance.Spec.RotationPeriod.Duration))
+		next = &rotation
+	}
+
+	// An expired key is replaced even when no rotation period has been configured
+	if current.ExpirationTime != nil && (next == nil || current.ExpirationTime.Before(next)) {
+		expiration := *current.ExpirationTime
+		next = &expiration
+	}
+
+	return next
+}
+
+func hasFinalizer(instance *v1alpha2.SigningKey) bool {
+	for _, finalizer := range instance.Finalizers {
+		if finalizer == signingKeyFinalizer {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeFinalizer(instance *v1alpha2.SigningKey) {
+	finalizers := []string{}
+	for _, finalizer := range instance.Finalizers {
+		if finalizer != signingKeyFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	instance.Finalizers = finalizers
+}
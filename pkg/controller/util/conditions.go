@@ -0,0 +1,69 @@
+package util
+
+import (
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/images"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxConditionHistory is the number of condition changes retained in the status of an ImageSigningRequest
+const MaxConditionHistory = 20
+
+func NewImageSigningCondition(conditionType images.ImageExecutionConditionType, conditionStatus corev1.ConditionStatus, reason string, message string) v1alpha2.ImageSigningCondition {
+
+	return v1alpha2.ImageSigningCondition{
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+		Status:             conditionStatus,
+		Type:               conditionType,
+	}
+
+}
+
+// FindImageSigningCondition returns the condition of the given type or nil when it has not been set
+func FindImageSigningCondition(status *v1alpha2.ImageSigningRequestStatus, conditionType images.ImageExecutionConditionType) *v1alpha2.ImageSigningCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// SetImageSigningCondition updates the condition of the same type in place, or adds it if none exists. The
+// LastTransitionTime of an existing condition is only moved when its status changes. Every change is recorded
+// in the bounded history of the status at the time it was made.
+func SetImageSigningCondition(status *v1alpha2.ImageSigningRequestStatus, condition v1alpha2.ImageSigningCondition) {
+
+	existing := FindImageSigningCondition(status, condition.Type)
+
+	if existing == nil {
+		status.Conditions = append(status.Conditions, condition)
+		appendImageSigningHistory(status, condition)
+		return
+	}
+
+	if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+		return
+	}
+
+	appendImageSigningHistory(status, condition)
+
+	if existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+
+	*existing = condition
+}
+
+func appendImageSigningHistory(status *v1alpha2.ImageSigningRequestStatus, condition v1alpha2.ImageSigningCondition) {
+
+	status.History = append(status.History, condition)
+
+	if len(status.History) > MaxConditionHistory {
+		status.History = status.History[len(status.History)-MaxConditionHistory:]
+	}
+}
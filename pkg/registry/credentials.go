@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Credential is a username and password used to authenticate with a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Credentials holds registry credentials keyed by registry host
+type Credentials map[string]Credential
+
+type dockerConfigEntry struct {
+	Auth     string `json:"auth,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// CredentialsFromSecret reads the credentials from a kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg secret
+func CredentialsFromSecret(secret *corev1.Secret) (Credentials, error) {
+	entries := map[string]dockerConfigEntry{}
+
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		config := dockerConfigJSON{}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("Error parsing pull secret '%s': %v", secret.Name, err)
+		}
+		entries = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &entries); err != nil {
+			return nil, fmt.Errorf("Error parsing pull secret '%s': %v", secret.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("Invalid pull secret format '%s' for Secret '%s'", secret.Type, secret.Name)
+	}
+
+	credentials := Credentials{}
+
+	for key, entry := range entries {
+		credential := Credential{Username: entry.Username, Password: entry.Password}
+
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("Error decoding auth for '%s' in pull secret '%s': %v", key, secret.Name, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) == 2 {
+				credential = Credential{Username: parts[0], Password: parts[1]}
+			}
+		}
+
+		credentials[normalizeHost(key)] = credential
+	}
+
+	return credentials, nil
+}
+
+// Merge adds the credentials of other for hosts that do not already have credentials
+func (c Credentials) Merge(other Credentials) {
+	for host, credential := range other {
+		if _, ok := c[host]; !ok {
+			c[host] = credential
+		}
+	}
+}
+
+// Lookup returns the credential for a registry host
+func (c Credentials) Lookup(host string) (Credential, bool) {
+	credential, ok := c[normalizeHost(host)]
+	return credential, ok
+}
+
+// normalizeHost reduces the keys found in docker configuration files, which may be URLs, to a registry host
+func normalizeHost(key string) string {
+	host := key
+
+	if strings.Contains(key, "://") {
+		if parsed, err := url.Parse(key); err == nil {
+			host = parsed.Host
+		}
+	}
+
+	host = strings.SplitN(host, "/", 2)[0]
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		host = "docker.io"
+	}
+
+	return host
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// Platform describes the platform an image in a manifest list runs on
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// String returns the platform in the form os/architecture[/variant]
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return fmt.Sprintf("%s/%s", p.OS, p.Architecture)
+	}
+	return fmt.Sprintf("%s/%s/%s", p.OS, p.Architecture, p.Variant)
+}
+
+// Matches reports whether the platform is selected by a filter in the form os/architecture[/variant]. A filter
+// without a variant matches every variant of the architecture.
+func (p Platform) Matches(filter string) bool {
+	parts := strings.Split(filter, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return false
+	}
+
+	if parts[0] != p.OS || parts[1] != p.Architecture {
+		return false
+	}
+
+	return len(parts) == 2 || parts[2] == p.Variant
+}
+
+// ManifestDescriptor references the manifest of one platform within a manifest list
+type ManifestDescriptor struct {
+	MediaType string        `json:"mediaType"`
+	Digest    digest.Digest `json:"digest"`
+	Size      int64         `json:"size"`
+	Platform  *Platform     `json:"platform,omitempty"`
+}
+
+// ManifestList is a Docker manifest list or OCI image index
+type ManifestList struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType,omitempty"`
+	Manifests     []ManifestDescriptor `json:"manifests"`
+}
+
+// IsManifestList reports whether a media type is that of a Docker manifest list or OCI image index
+func IsManifestList(mediaType string) bool {
+	mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+	return mediaType == MediaTypeDockerManifestList || mediaType == MediaTypeOCIIndex
+}
+
+// ParseManifestList reads a Docker manifest list or OCI image index
+func ParseManifestList(manifest []byte) (*ManifestList, error) {
+	list := &ManifestList{}
+
+	if err := json.Unmarshal(manifest, list); err != nil {
+		return nil, fmt.Errorf("Invalid manifest list: %v", err)
+	}
+
+	return list, nil
+}
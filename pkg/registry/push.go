@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// PushBlob uploads content to the repository of a reference unless the registry already has it
+func (c *Client) PushBlob(ref reference.Named, content []byte) (digest.Digest, error) {
+
+	blobDigest := digest.FromBytes(content)
+
+	head, err := http.NewRequest(http.MethodHead, c.URL(ref, "blobs", blobDigest.String()), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.Do(head, ref, "pull,push")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return blobDigest, nil
+	}
+
+	start, err := http.NewRequest(http.MethodPost, c.URL(ref, "blobs", "uploads/"), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err = c.Do(start, ref, "pull,push")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", responseError(resp, ref)
+	}
+
+	location, err := start.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", fmt.Errorf("Invalid upload location returned by registry '%s': %v", reference.Domain(ref), err)
+	}
+
+	query := location.Query()
+	query.Set("digest", blobDigest.String())
+	location.RawQuery = query.Encode()
+
+	upload, err := http.NewRequest(http.MethodPut, location.String(), bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	upload.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = c.Do(upload, ref, "pull,push")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", responseError(resp, ref)
+	}
+
+	return blobDigest, nil
+}
+
+// PutManifest uploads a manifest to the repository of a reference under a tag and returns its digest
+func (c *Client) PutManifest(ref reference.Named, tag string, mediaType string, manifest []byte) (digest.Digest, error) {
+
+	req, err := http.NewRequest(http.MethodPut, c.URL(ref, "manifests", url.PathEscape(tag)), bytes.NewReader(manifest))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mediaType)
+
+	resp, err := c.Do(req, ref, "pull,push")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", responseError(resp, ref)
+	}
+
+	return digest.FromBytes(manifest), nil
+}
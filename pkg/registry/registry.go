@@ -0,0 +1,313 @@
+package registry
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// Manifest media types understood by the operator
+const (
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+var manifestMediaTypes = []string{
+	MediaTypeDockerManifest,
+	MediaTypeDockerManifestList,
+	MediaTypeOCIManifest,
+	MediaTypeOCIIndex,
+}
+
+// Client talks to container registries using the Docker Registry HTTP API V2
+type Client struct {
+	http        *http.Client
+	credentials Credentials
+}
+
+// NewClient returns a Client authenticating with the given credentials. TLS certificates are not verified
+// when tlsVerify is false.
+func NewClient(credentials Credentials, tlsVerify bool) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !tlsVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if credentials == nil {
+		credentials = Credentials{}
+	}
+
+	return &Client{
+		http:        &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		credentials: credentials,
+	}
+}
+
+// ParseReference parses an image location such as quay.io/redhat-cop/image-security:latest, expanding short
+// Docker Hub names the same way the container runtime does
+func ParseReference(image string) (reference.Named, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid image reference '%s': %v", image, err)
+	}
+
+	return reference.TagNameOnly(named), nil
+}
+
+// Host returns the host serving the registry API for a reference
+func Host(ref reference.Named) string {
+	host := reference.Domain(ref)
+	if host == "docker.io" {
+		return "registry-1.docker.io"
+	}
+
+	return host
+}
+
+// ResolveDigest returns the digest and media type of the manifest a reference points to
+func (c *Client) ResolveDigest(ref reference.Named) (digest.Digest, string, error) {
+
+	if digested, ok := ref.(reference.Digested); ok {
+		_, mediaType, err := c.manifestHead(ref, digested.Digest().String())
+		return digested.Digest(), mediaType, err
+	}
+
+	tagged, ok := ref.(reference.Tagged)
+	if !ok {
+		return "", "", fmt.Errorf("Image reference '%s' has neither a tag nor a digest", ref.String())
+	}
+
+	return c.manifestHead(ref, tagged.Tag())
+}
+
+// GetManifest returns the manifest a reference points to along with its media type
+func (c *Client) GetManifest(ref reference.Named, tagOrDigest string) ([]byte, string, error) {
+
+	req, err := http.NewRequest(http.MethodGet, c.URL(ref, "manifests", tagOrDigest), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := c.Do(req, ref, "pull")
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", responseError(resp, ref)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+func (c *Client) manifestHead(ref reference.Named, tagOrDigest string) (digest.Digest, string, error) {
+
+	req, err := http.NewRequest(http.MethodHead, c.URL(ref, "manifests", tagOrDigest), nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	resp, err := c.Do(req, ref, "pull")
+	if err != nil {
+		return "", "", err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", responseError(resp, ref)
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+
+	if value := resp.Header.Get("Docker-Content-Digest"); value != "" {
+		parsed, err := digest.Parse(value)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid digest '%s' returned for '%s': %v", value, ref.String(), err)
+		}
+		return parsed, mediaType, nil
+	}
+
+	// Not every registry returns the digest on a HEAD request, in which case it is computed from the manifest
+	manifest, mediaType, err := c.GetManifest(ref, tagOrDigest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return digest.FromBytes(manifest), mediaType, nil
+}
+
+// URL returns the address of a registry API resource, such as the manifests of the repository of a reference
+func (c *Client) URL(ref reference.Named, resource string, name string) string {
+	return fmt.Sprintf("https://%s/v2/%s/%s/%s", Host(ref), reference.Path(ref), resource, name)
+}
+
+// Do sends a request to the registry of the reference, authenticating with the credentials for the registry
+// when the registry asks for them. Actions are the repository scope actions requested from token servers,
+// such as "pull" or "pull,push".
+func (c *Client) Do(req *http.Request, ref reference.Named, actions string) (*http.Response, error) {
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	credential, hasCredential := c.credentials.Lookup(reference.Domain(ref))
+
+	retry, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(strings.ToLower(challenge), "bearer "):
+		token, err := c.token(challenge, fmt.Sprintf("repository:%s:%s", reference.Path(ref), actions), credential, hasCredential)
+		if err != nil {
+			return nil, err
+		}
+		retry.Header.Set("Authorization", "Bearer "+token)
+	case strings.HasPrefix(strings.ToLower(challenge), "basic ") && hasCredential:
+		retry.SetBasicAuth(credential.Username, credential.Password)
+	default:
+		return nil, fmt.Errorf("Unauthorized to access '%s' on registry '%s'", reference.Path(ref), reference.Domain(ref))
+	}
+
+	return c.http.Do(retry)
+}
+
+// token obtains a bearer token from the token server named in a WWW-Authenticate challenge
+func (c *Client) token(challenge string, scope string, credential Credential, hasCredential bool) (string, error) {
+	params := parseChallenge(challenge)
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("Invalid authentication challenge '%s'", challenge)
+	}
+
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if hasCredential {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Token server '%s' returned '%s'", realm.Host, resp.Status)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
+
+// parseChallenge reads the parameters of a challenge such as: Bearer realm="https://auth.example.com/token",service="registry"
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+
+	parts := strings.SplitN(challenge, " ", 2)
+	if len(parts) != 2 {
+		return params
+	}
+
+	for _, param := range strings.Split(parts[1], ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(keyValue) == 2 {
+			params[strings.ToLower(keyValue[0])] = strings.Trim(keyValue[1], "\"")
+		}
+	}
+
+	return params
+}
+
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("Unable to resend request to '%s' after authenticating", req.URL.Host)
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	return retry, nil
+}
+
+func responseError(resp *http.Response, ref reference.Named) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode == http.StatusNotFound {
+		return notFoundError{ref: ref}
+	}
+
+	return fmt.Errorf("Registry '%s' returned '%s' for '%s': %s", reference.Domain(ref), resp.Status, ref.String(), strings.TrimSpace(string(body)))
+}
+
+// notFoundError is returned when the registry does not have the requested image or blob
+type notFoundError struct {
+	ref reference.Named
+}
+
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("Image '%s' Not Found", e.ref.String())
+}
+
+// IsNotFound reports whether an error was caused by the registry not having the requested image or blob
+func IsNotFound(err error) bool {
+	_, ok := err.(notFoundError)
+	return ok
+}
@@ -0,0 +1,114 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// SupportsSignaturesHeader is returned by registries implementing the signature extension API
+const SupportsSignaturesHeader = "X-Registry-Supports-Signatures"
+
+// extensionSignatureSchemaVersion is the version of the signatures exchanged with the signature extension API
+const extensionSignatureSchemaVersion = 2
+
+// ExtensionSignature is a signature exchanged with the signature extension API
+type ExtensionSignature struct {
+	Version int    `json:"schemaVersion"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content []byte `json:"content"`
+}
+
+// extensionSignatureList is the list of signatures of an image returned by the signature extension API
+type extensionSignatureList struct {
+	Signatures []ExtensionSignature `json:"signatures"`
+}
+
+// SupportsSignatures reports whether the registry of a reference implements the signature extension API
+func (c *Client) SupportsSignatures(ref reference.Named) (bool, error) {
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/v2/", Host(ref)), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.Do(req, ref, "pull")
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get(SupportsSignaturesHeader) == "1", nil
+}
+
+// GetSignatures returns the signatures stored by the registry for the manifest digest of an image
+func (c *Client) GetSignatures(ref reference.Named, manifestDigest digest.Digest) ([]ExtensionSignature, error) {
+
+	req, err := http.NewRequest(http.MethodGet, c.extensionURL(ref, manifestDigest), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req, ref, "pull")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return []ExtensionSignature{}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, ref)
+	}
+
+	list := extensionSignatureList{}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("Invalid signatures returned by registry '%s': %v", reference.Domain(ref), err)
+	}
+
+	return list.Signatures, nil
+}
+
+// PutSignature stores a signature of the manifest digest of an image with the registry. The name must be unique
+// among the signatures of the image.
+func (c *Client) PutSignature(ref reference.Named, manifestDigest digest.Digest, name string, signatureType string, content []byte) error {
+
+	body, err := json.Marshal(ExtensionSignature{
+		Version: extensionSignatureSchemaVersion,
+		Name:    name,
+		Type:    signatureType,
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, c.extensionURL(ref, manifestDigest), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(req, ref, "pull,push")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return responseError(resp, ref)
+	}
+
+	return nil
+}
+
+func (c *Client) extensionURL(ref reference.Named, manifestDigest digest.Digest) string {
+	return fmt.Sprintf("https://%s/extensions/v2/%s/signatures/%s", Host(ref), reference.Path(ref), manifestDigest)
+}
@@ -0,0 +1,231 @@
+package cosign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Media types and annotations of the signature artifacts read by cosign
+const (
+	MediaTypeSimpleSigning = "application/vnd.dev.cosign.simplesigning.v1+json"
+	MediaTypeOCIConfig     = "application/vnd.oci.image.config.v1+json"
+	MediaTypeOCIManifest   = "application/vnd.oci.image.manifest.v1+json"
+	SignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	SignatureType          = "cosign container image signature"
+)
+
+// Keys of the cosign secret
+const (
+	PrivateKeyKey = "cosign.key"
+	PasswordKey   = "cosign.password"
+)
+
+// Descriptor references a blob from a manifest
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Size        int64             `json:"size"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is the OCI image manifest holding the signatures of an image, one layer per signature
+type Manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// SignatureTag returns the tag cosign stores the signatures of a manifest digest under
+func SignatureTag(manifestDigest digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", manifestDigest.Algorithm(), manifestDigest.Hex())
+}
+
+// LoadPrivateKey reads an ECDSA or ed25519 private key. Keys generated by 'cosign generate-key-pair' are
+// decrypted with the password, unencrypted PKCS#8 and EC keys are used as they are.
+func LoadPrivateKey(data []byte, password []byte) (crypto.Signer, error) {
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("Cosign key is not PEM encoded")
+	}
+
+	der := block.Bytes
+
+	switch block.Type {
+	case "ENCRYPTED COSIGN PRIVATE KEY", "ENCRYPTED SIGSTORE PRIVATE KEY":
+		decrypted, err := decrypt(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		der = decrypted
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(der)
+	case "PRIVATE KEY":
+	default:
+		return nil, fmt.Errorf("Unsupported cosign key type '%s'", block.Type)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	switch signer := key.(type) {
+	case *ecdsa.PrivateKey:
+		return signer, nil
+	case ed25519.PrivateKey:
+		return signer, nil
+	}
+
+	return nil, errors.New("Cosign key must be an ECDSA or ed25519 key")
+}
+
+func decrypt(data []byte, password []byte) ([]byte, error) {
+	key := encryptedKey{}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("Invalid encrypted cosign key: %v", err)
+	}
+
+	if key.KDF.Name != "scrypt" || key.Cipher.Name != "nacl/secretbox" || len(key.Cipher.Nonce) != 24 {
+		return nil, fmt.Errorf("Unsupported cosign key encryption '%s' with '%s'", key.KDF.Name, key.Cipher.Name)
+	}
+
+	derived, err := scrypt.Key(password, key.KDF.Salt, key.KDF.Params.N, key.KDF.Params.R, key.KDF.Params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	var secretKey [32]byte
+	var nonce [24]byte
+	copy(secretKey[:], derived)
+	copy(nonce[:], key.Cipher.Nonce)
+
+	decrypted, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.New("Unable to decrypt cosign key, the password is incorrect")
+	}
+
+	return decrypted, nil
+}
+
+// NewPayload returns the simple signing payload cosign signs for an image
+func NewPayload(dockerReference string, manifestDigest digest.Digest) ([]byte, error) {
+	payload := map[string]interface{}{
+		"critical": map[string]interface{}{
+			"identity": map[string]string{"docker-reference": dockerReference},
+			"image":    map[string]string{"docker-manifest-digest": manifestDigest.String()},
+			"type":     SignatureType,
+		},
+		"optional": nil,
+	}
+
+	return json.Marshal(payload)
+}
+
+// Sign signs a payload the way cosign does and returns the base64 encoded signature
+func Sign(signer crypto.Signer, payload []byte) (string, error) {
+	var signature []byte
+	var err error
+
+	switch signer.(type) {
+	case ed25519.PrivateKey:
+		signature, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	default:
+		hashed := sha256.Sum256(payload)
+		signature, err = signer.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// AppendSignature adds a signature layer to the existing signature manifest of an image, or starts a new one
+// when existing is empty. The config blob to upload is returned along with the manifest, which is nil when the
+// signature is already present.
+func AppendSignature(existing []byte, payload []byte, signature string) ([]byte, []byte, error) {
+
+	manifest := Manifest{SchemaVersion: 2, MediaType: MediaTypeOCIManifest}
+
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &manifest); err != nil {
+			return nil, nil, fmt.Errorf("Invalid signature manifest: %v", err)
+		}
+	}
+
+	payloadDigest := digest.FromBytes(payload)
+
+	for _, layer := range manifest.Layers {
+		if layer.Digest == payloadDigest.String() && layer.Annotations[SignatureAnnotation] == signature {
+			return nil, nil, nil
+		}
+	}
+
+	manifest.Layers = append(manifest.Layers, Descriptor{
+		MediaType:   MediaTypeSimpleSigning,
+		Size:        int64(len(payload)),
+		Digest:      payloadDigest.String(),
+		Annotations: map[string]string{SignatureAnnotation: signature},
+	})
+
+	diffIDs := []string{}
+	for _, layer := range manifest.Layers {
+		diffIDs = append(diffIDs, layer.Digest)
+	}
+
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": "",
+		"os":           "",
+		"config":       map[string]interface{}{},
+		"rootfs":       map[string]interface{}{"type": "layers", "diff_ids": diffIDs},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	manifest.Config = Descriptor{
+		MediaType: MediaTypeOCIConfig,
+		Size:      int64(len(config)),
+		Digest:    digest.FromBytes(config).String(),
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return content, config, nil
+}
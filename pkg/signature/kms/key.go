@@ -0,0 +1,18 @@
+package kms
+
+import (
+	"crypto"
+	"time"
+)
+
+// Key is a key pair held by a key management service or token, which signs without handing out its private key
+type Key interface {
+	crypto.Signer
+	// CreationTime of the key, which the fingerprint of the OpenPGP key wrapping it depends on
+	CreationTime() time.Time
+}
+
+var (
+	_ Key = &VaultTransitKey{}
+	_ Key = &PKCS11Key{}
+)
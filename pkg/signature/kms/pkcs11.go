@@ -0,0 +1,123 @@
+package kms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// pkcs11Tool is the OpenSC command used to access PKCS#11 tokens
+const pkcs11Tool = "pkcs11-tool"
+
+// pinEnv is the environment variable the PIN of a token is handed to pkcs11-tool in, as command line arguments can be
+// read by any process
+const pinEnv = "PKCS11_TOOL_PIN"
+
+// digestInfoPrefixes are the DER encoded DigestInfo prefixes that RSA PKCS #1 v1.5 signatures are made over
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// PKCS11Key is a key pair of a PKCS#11 token, such as a hardware security module or SoftHSM. The token is accessed
+// with pkcs11-tool of OpenSC, which must be installed in the operator image along with the PKCS#11 module of the
+// token. Only the digest of what is signed is passed to the token.
+type PKCS11Key struct {
+	module     string
+	tokenLabel string
+	id         string
+	pin        string
+	public     crypto.PublicKey
+}
+
+// NewPKCS11Key reads the public key of the key pair with the hex encoded ID on the token with the given label
+func NewPKCS11Key(module string, tokenLabel string, id string, pin string) (*PKCS11Key, error) {
+
+	key := &PKCS11Key{module: module, tokenLabel: tokenLabel, id: id, pin: pin}
+
+	der, err := key.run(nil, "--read-object", "--type", "pubkey", "--id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	public, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid public key '%s' on PKCS#11 token '%s': %v", id, tokenLabel, err)
+	}
+
+	key.public = public
+
+	return key, nil
+}
+
+// Public returns the public key of the key pair
+func (k *PKCS11Key) Public() crypto.PublicKey {
+	return k.public
+}
+
+// CreationTime of the key. Tokens do not record when a key was created, so the Unix epoch is used to keep the
+// fingerprint of the OpenPGP key wrapping it stable.
+func (k *PKCS11Key) CreationTime() time.Time {
+	return time.Unix(0, 0)
+}
+
+// Sign has the token sign a digest
+func (k *PKCS11Key) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+
+	switch k.public.(type) {
+	case *ecdsa.PublicKey:
+		return k.run(digest, "--sign", "--id", k.id, "--mechanism", "ECDSA", "--signature-format", "openssl", "--login", "--pin", "env:"+pinEnv)
+
+	case *rsa.PublicKey:
+		prefix, ok := digestInfoPrefixes[opts.HashFunc()]
+		if !ok {
+			return nil, fmt.Errorf("Hash function %v is not supported for PKCS#11 RSA keys", opts.HashFunc())
+		}
+		return k.run(append(append([]byte{}, prefix...), digest...), "--sign", "--id", k.id, "--mechanism", "RSA-PKCS", "--login", "--pin", "env:"+pinEnv)
+	}
+
+	return nil, fmt.Errorf("Unsupported PKCS#11 key type %T", k.public)
+}
+
+// run calls pkcs11-tool on the token, passing it input through a file and the PIN through its environment, and
+// returning what it writes to its output file
+func (k *PKCS11Key) run(input []byte, args ...string) ([]byte, error) {
+
+	dir, err := ioutil.TempDir("", "pkcs11")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	outputFile := dir + "/output"
+	args = append([]string{"--module", k.module, "--token-label", k.tokenLabel, "--output-file", outputFile}, args...)
+
+	if input != nil {
+		inputFile := dir + "/input"
+		if err := ioutil.WriteFile(inputFile, input, 0600); err != nil {
+			return nil, err
+		}
+		args = append(args, "--input-file", inputFile)
+	}
+
+	stderr := &bytes.Buffer{}
+	command := exec.Command(pkcs11Tool, args...)
+	command.Env = append(os.Environ(), pinEnv+"="+k.pin)
+	command.Stderr = stderr
+
+	if err := command.Run(); err != nil {
+		return nil, fmt.Errorf("Error accessing PKCS#11 token '%s': %v: %s", k.tokenLabel, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return ioutil.ReadFile(outputFile)
+}
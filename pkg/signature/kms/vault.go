@@ -0,0 +1,201 @@
+package kms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultVaultTransitMount is the path the transit secrets engine is mounted at unless configured otherwise
+const DefaultVaultTransitMount = "transit"
+
+// VaultTransitKey is a key of the HashiCorp Vault transit secrets engine. Only the digest of what is signed is sent
+// to Vault, which signs it without the private key ever leaving Vault.
+type VaultTransitKey struct {
+	http    *http.Client
+	address string
+	mount   string
+	name    string
+	token   string
+	version int
+	public  crypto.PublicKey
+	created time.Time
+}
+
+// vaultKey is the part of the response to reading a transit key used by the operator
+type vaultKey struct {
+	Data struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			CreationTime string `json:"creation_time"`
+			PublicKey    string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+// NewVaultTransitKey reads the public half of the latest version of a transit key, which must be an rsa or ecdsa
+// key. TLS certificates of Vault are not verified when tlsVerify is false.
+func NewVaultTransitKey(address string, mount string, name string, token string, tlsVerify bool) (*VaultTransitKey, error) {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !tlsVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if mount == "" {
+		mount = DefaultVaultTransitMount
+	}
+
+	key := &VaultTransitKey{
+		http:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		address: strings.TrimSuffix(address, "/"),
+		mount:   strings.Trim(mount, "/"),
+		name:    name,
+		token:   token,
+	}
+
+	response := vaultKey{}
+	if err := key.do(http.MethodGet, "keys/"+name, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(response.Data.Type, "rsa-") && !strings.HasPrefix(response.Data.Type, "ecdsa-") {
+		return nil, fmt.Errorf("Vault key '%s' of type '%s' cannot make OpenPGP signatures, an rsa or ecdsa key is required", name, response.Data.Type)
+	}
+
+	latest, ok := response.Data.Keys[strconv.Itoa(response.Data.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("Vault key '%s' has no version %d", name, response.Data.LatestVersion)
+	}
+
+	block, _ := pem.Decode([]byte(latest.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("Vault key '%s' returned no public key", name)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid public key of Vault key '%s': %v", name, err)
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, latest.CreationTime)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid creation time of Vault key '%s': %v", name, err)
+	}
+
+	key.version = response.Data.LatestVersion
+	key.public = public
+	key.created = created
+
+	return key, nil
+}
+
+// Public returns the public key of the version of the key that signs
+func (k *VaultTransitKey) Public() crypto.PublicKey {
+	return k.public
+}
+
+// CreationTime is when the version of the key that signs was created
+func (k *VaultTransitKey) CreationTime() time.Time {
+	return k.created
+}
+
+// Sign has Vault sign a digest
+func (k *VaultTransitKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+
+	hashAlgorithm, ok := vaultHashAlgorithms[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("Hash function %v is not supported by Vault", opts.HashFunc())
+	}
+
+	request := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"hash_algorithm":       hashAlgorithm,
+		"key_version":          k.version,
+		"marshaling_algorithm": "asn1",
+	}
+
+	if _, isRSA := k.public.(*rsa.PublicKey); isRSA {
+		request["signature_algorithm"] = "pkcs1v15"
+	}
+
+	response := struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}{}
+
+	if err := k.do(http.MethodPost, "sign/"+k.name, request, &response); err != nil {
+		return nil, err
+	}
+
+	// Signatures are returned as vault:v<version>:<base64 signature>
+	parts := strings.Split(response.Data.Signature, ":")
+	signature, err := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature returned by Vault: %v", err)
+	}
+
+	return signature, nil
+}
+
+var vaultHashAlgorithms = map[crypto.Hash]string{
+	crypto.SHA256: "sha2-256",
+	crypto.SHA384: "sha2-384",
+	crypto.SHA512: "sha2-512",
+}
+
+// do calls an endpoint of the transit secrets engine
+func (k *VaultTransitKey) do(method string, path string, body interface{}, result interface{}) error {
+
+	var content io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s/%s", k.address, k.mount, path), content)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", k.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := k.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errorResponse := struct {
+			Errors []string `json:"errors"`
+		}{}
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		if json.Unmarshal(message, &errorResponse) == nil && len(errorResponse.Errors) > 0 {
+			return fmt.Errorf("Vault returned '%s' for key '%s': %s", resp.Status, k.name, strings.Join(errorResponse.Errors, "; "))
+		}
+		return fmt.Errorf("Vault returned '%s' for key '%s'", resp.Status, k.name)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
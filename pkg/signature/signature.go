@@ -0,0 +1,351 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	"golang.org/x/crypto/openpgp/s2k"
+)
+
+// SignatureType is the type of the simple signing payload written by podman and skopeo
+const SignatureType = "atomic container signature"
+
+// Payload is the simple signing format signed by 'podman image sign'
+type Payload struct {
+	Critical Critical               `json:"critical"`
+	Optional map[string]interface{} `json:"optional,omitempty"`
+}
+
+// Critical holds the claims a verifier must check
+type Critical struct {
+	Type     string   `json:"type"`
+	Image    Image    `json:"image"`
+	Identity Identity `json:"identity"`
+}
+
+// Image identifies the signed manifest
+type Image struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// Identity is the reference the image was signed as
+type Identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+// Verified describes a signature that has been verified
+type Verified struct {
+	// Fingerprint of the key that made the signature
+	Fingerprint string
+	// Signer is the primary identity of the key that made the signature
+	Signer string
+	// DockerReference the image was signed as
+	DockerReference string
+}
+
+// Keyring reads ASCII armored and binary public keys into a single keyring
+func Keyring(keys ...[]byte) (openpgp.EntityList, error) {
+	keyring := openpgp.EntityList{}
+
+	for _, key := range keys {
+		if len(bytes.TrimSpace(key)) == 0 {
+			continue
+		}
+
+		var entities openpgp.EntityList
+		var err error
+
+		if bytes.HasPrefix(bytes.TrimSpace(key), []byte("-----BEGIN")) {
+			entities, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+		} else {
+			entities, err = openpgp.ReadKeyRing(bytes.NewReader(key))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		keyring = append(keyring, entities...)
+	}
+
+	return keyring, nil
+}
+
+// Verify checks that a signature was made by a key in the keyring over a payload claiming the given manifest
+// digest. As with the matchRepoDigestOrExact policy of containers/image, the identity must name the same repository
+// as a reference by digest, and must be exactly the reference used otherwise, tag included.
+func Verify(signature []byte, keyring openpgp.EntityList, ref reference.Named, manifestDigest digest.Digest) (*Verified, error) {
+
+	message, err := openpgp.ReadMessage(bytes.NewReader(signature), keyring, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %v", err)
+	}
+
+	if !message.IsSigned || message.SignedBy == nil {
+		return nil, errors.New("Signature was not made by a trusted key")
+	}
+
+	// The signature is only checked once the whole body has been read
+	content, err := ioutil.ReadAll(message.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %v", err)
+	}
+
+	if message.SignatureError != nil {
+		return nil, fmt.Errorf("Invalid signature: %v", message.SignatureError)
+	}
+
+	payload := Payload{}
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return nil, fmt.Errorf("Invalid signature payload: %v", err)
+	}
+
+	if payload.Critical.Type != SignatureType {
+		return nil, fmt.Errorf("Unsupported signature type '%s'", payload.Critical.Type)
+	}
+
+	if payload.Critical.Image.DockerManifestDigest != manifestDigest.String() {
+		return nil, fmt.Errorf("Signature is for digest '%s' rather than '%s'", payload.Critical.Image.DockerManifestDigest, manifestDigest)
+	}
+
+	signedAs, err := reference.ParseNormalizedNamed(payload.Critical.Identity.DockerReference)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid identity '%s' in signature: %v", payload.Critical.Identity.DockerReference, err)
+	}
+
+	if _, digested := ref.(reference.Digested); digested {
+		if signedAs.Name() != ref.Name() {
+			return nil, fmt.Errorf("Signature is for repository '%s' rather than '%s'", signedAs.Name(), ref.Name())
+		}
+	} else if signedAs.String() != ref.String() {
+		return nil, fmt.Errorf("Signature is for '%s' rather than '%s'", signedAs.String(), ref.String())
+	}
+
+	return &Verified{
+		Fingerprint:     fmt.Sprintf("%X", message.SignedBy.PublicKey.Fingerprint),
+		Signer:          primaryIdentity(message.SignedBy.Entity),
+		DockerReference: payload.Critical.Identity.DockerReference,
+	}, nil
+}
+
+// UnverifiedPayload reads the payload of a signature without verifying the signature, to check the claims of a
+// signature before it is stored
+func UnverifiedPayload(signature []byte) (*Payload, error) {
+
+	message, err := openpgp.ReadMessage(bytes.NewReader(signature), openpgp.EntityList{}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %v", err)
+	}
+
+	if !message.IsSigned {
+		return nil, errors.New("Signature is not signed")
+	}
+
+	content, err := ioutil.ReadAll(message.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %v", err)
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(content, payload); err != nil {
+		return nil, fmt.Errorf("Invalid signature payload: %v", err)
+	}
+
+	if payload.Critical.Type != SignatureType {
+		return nil, fmt.Errorf("Unsupported signature type '%s'", payload.Critical.Type)
+	}
+
+	return payload, nil
+}
+
+// IssuerKeyID returns the ID of the key that claims to have made a signature, without verifying the signature. An
+// empty string is returned when the signature cannot be read.
+func IssuerKeyID(signature []byte) string {
+
+	message, err := openpgp.ReadMessage(bytes.NewReader(signature), openpgp.EntityList{}, nil, nil)
+	if err != nil || !message.IsSigned {
+		return ""
+	}
+
+	return fmt.Sprintf("%016X", message.SignedByKeyId)
+}
+
+// primaryIdentity returns the name of the primary identity of a key, or of any identity when none is marked primary
+func primaryIdentity(entity *openpgp.Entity) string {
+	if entity == nil {
+		return ""
+	}
+
+	name := ""
+	for _, identity := range entity.Identities {
+		if identity.SelfSignature != nil && identity.SelfSignature.IsPrimaryId != nil && *identity.SelfSignature.IsPrimaryId {
+			return identity.Name
+		}
+		if name == "" || identity.Name < name {
+			name = identity.Name
+		}
+	}
+
+	return name
+}
+
+// NewPayload returns the payload claiming that the manifest digest is the image the reference names
+func NewPayload(dockerReference string, manifestDigest digest.Digest, creator string, timestamp time.Time) Payload {
+	return Payload{
+		Critical: Critical{
+			Type:     SignatureType,
+			Image:    Image{DockerManifestDigest: manifestDigest.String()},
+			Identity: Identity{DockerReference: dockerReference},
+		},
+		Optional: map[string]interface{}{
+			"creator":   creator,
+			"timestamp": timestamp.Unix(),
+		},
+	}
+}
+
+// Sign produces an OpenPGP signed message over the payload in the format read by containers/image
+func Sign(payload Payload, signer *openpgp.Entity) ([]byte, error) {
+
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := &bytes.Buffer{}
+	writer, err := openpgp.Sign(signed, signer, nil, &packet.Config{DefaultHash: crypto.SHA256})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return signed.Bytes(), nil
+}
+
+// NewEntity returns an OpenPGP key wrapping a signer whose private key is held elsewhere, such as in a key
+// management service or hardware security module. Only RSA and ECDSA keys are supported. The fingerprint of the
+// key depends on its creation time, which must not change for signatures to keep verifying with the same key.
+func NewEntity(signer crypto.Signer, name string, comment string, email string, created time.Time) (*openpgp.Entity, error) {
+
+	var privateKey *packet.PrivateKey
+	switch publicKey := signer.Public().(type) {
+	case *rsa.PublicKey:
+		privateKey = packet.NewSignerPrivateKey(created, signer)
+		// Signing only RSA keys are deprecated, so the key is published as a regular RSA key
+		privateKey.PublicKey = *packet.NewRSAPublicKey(created, publicKey)
+	case *ecdsa.PublicKey:
+		privateKey = packet.NewSignerPrivateKey(created, signer)
+	default:
+		return nil, fmt.Errorf("Unsupported public key type %T, only RSA and ECDSA keys can sign", publicKey)
+	}
+
+	userID := packet.NewUserId(name, comment, email)
+	if userID == nil {
+		return nil, fmt.Errorf("Invalid identity '%s'", name)
+	}
+
+	// Without a preferred hash, openpgp.Sign falls back to RIPEMD-160, which is not compiled in
+	sha256, _ := s2k.HashToHashId(crypto.SHA256)
+
+	primary := true
+	selfSignature := &packet.Signature{
+		SigType:       packet.SigTypePositiveCert,
+		PubKeyAlgo:    privateKey.PublicKey.PubKeyAlgo,
+		Hash:          crypto.SHA256,
+		CreationTime:  created,
+		IssuerKeyId:   &privateKey.PublicKey.KeyId,
+		IsPrimaryId:   &primary,
+		FlagsValid:    true,
+		FlagSign:      true,
+		FlagCertify:   true,
+		PreferredHash: []uint8{sha256},
+	}
+
+	if err := selfSignature.SignUserId(userID.Id, &privateKey.PublicKey, privateKey, nil); err != nil {
+		return nil, err
+	}
+
+	return &openpgp.Entity{
+		PrimaryKey: &privateKey.PublicKey,
+		PrivateKey: privateKey,
+		Identities: map[string]*openpgp.Identity{
+			userID.Id: {Name: userID.Id, UserId: userID, SelfSignature: selfSignature},
+		},
+	}, nil
+}
+
+// ArmoredPublicKey returns the ASCII armored public key of an entity, as imported by gpg
+func ArmoredPublicKey(entity *openpgp.Entity) (string, error) {
+
+	armored := &bytes.Buffer{}
+	writer, err := armor.Encode(armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if err := entity.Serialize(writer); err != nil {
+		return "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return armored.String(), nil
+}
+
+// SigningEntity selects the private key to sign with from a keyring. signBy may be the email or name of an
+// identity, the fingerprint of the key or its key ID.
+func SigningEntity(keyring openpgp.EntityList, signBy string) (*openpgp.Entity, error) {
+
+	for _, entity := range keyring {
+		if entity.PrivateKey == nil || !matchesSignBy(entity, signBy) {
+			continue
+		}
+
+		if entity.PrivateKey.Encrypted {
+			return nil, fmt.Errorf("Signing key '%s' is protected by a passphrase, which is not supported", signBy)
+		}
+
+		return entity, nil
+	}
+
+	return nil, fmt.Errorf("No private key found for '%s'", signBy)
+}
+
+func matchesSignBy(entity *openpgp.Entity, signBy string) bool {
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+	keyID := strings.ToUpper(strings.TrimPrefix(signBy, "0x"))
+
+	if len(keyID) >= 8 && strings.HasSuffix(fingerprint, keyID) {
+		return true
+	}
+
+	for _, identity := range entity.Identities {
+		if identity.UserId.Email == signBy || identity.UserId.Name == signBy || identity.Name == signBy {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,235 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/openpgp"
+)
+
+var (
+	created        = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	manifestDigest = digest.FromString("manifest")
+	otherDigest    = digest.FromString("other manifest")
+)
+
+func newTestEntity(t *testing.T, signer crypto.Signer, email string) *openpgp.Entity {
+	entity, err := NewEntity(signer, "Image Signing", "test", email, created)
+	if err != nil {
+		t.Fatalf("NewEntity() error = %v", err)
+	}
+
+	return entity
+}
+
+func newECDSAEntity(t *testing.T, email string) *openpgp.Entity {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return newTestEntity(t, key, email)
+}
+
+func newRSAEntity(t *testing.T, email string) *openpgp.Entity {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return newTestEntity(t, key, email)
+}
+
+// signAs signs the manifest digest as the docker reference with the key of the keyring selected by signBy
+func signAs(t *testing.T, keyring openpgp.EntityList, signBy string, dockerReference string) []byte {
+	signer, err := SigningEntity(keyring, signBy)
+	if err != nil {
+		t.Fatalf("SigningEntity() error = %v", err)
+	}
+
+	signature, err := Sign(NewPayload(dockerReference, manifestDigest, "image-security", created), signer)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	return signature
+}
+
+func parse(t *testing.T, image string) reference.Named {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		t.Fatalf("ParseNormalizedNamed() error = %v", err)
+	}
+
+	return ref
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		entity func(t *testing.T, email string) *openpgp.Entity
+	}{
+		{name: "ECDSA key", entity: newECDSAEntity},
+		{name: "RSA key", entity: newRSAEntity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			entity := test.entity(t, "release@example.com")
+			keyring := openpgp.EntityList{entity}
+
+			signature := signAs(t, keyring, "release@example.com", "quay.io/redhat-cop/dotnet-example:latest")
+
+			// Only the public key is trusted by verifiers
+			armored, err := ArmoredPublicKey(entity)
+			if err != nil {
+				t.Fatalf("ArmoredPublicKey() error = %v", err)
+			}
+			trusted, err := Keyring([]byte(armored))
+			if err != nil {
+				t.Fatalf("Keyring() error = %v", err)
+			}
+
+			verified, err := Verify(signature, trusted, parse(t, "quay.io/redhat-cop/dotnet-example:latest"), manifestDigest)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if want := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint); verified.Fingerprint != want {
+				t.Errorf("Verify() fingerprint = %s, want %s", verified.Fingerprint, want)
+			}
+			if verified.Signer != "Image Signing (test) <release@example.com>" {
+				t.Errorf("Verify() signer = %q", verified.Signer)
+			}
+			if verified.DockerReference != "quay.io/redhat-cop/dotnet-example:latest" {
+				t.Errorf("Verify() docker reference = %q", verified.DockerReference)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	signingEntity := newECDSAEntity(t, "release@example.com")
+	untrustedEntity := newECDSAEntity(t, "untrusted@example.com")
+	keyring := openpgp.EntityList{signingEntity, untrustedEntity}
+
+	signature := signAs(t, keyring, "release@example.com", "quay.io/redhat-cop/dotnet-example:latest")
+	untrusted := signAs(t, keyring, "untrusted@example.com", "quay.io/redhat-cop/dotnet-example:latest")
+
+	trusted := openpgp.EntityList{signingEntity}
+
+	tests := []struct {
+		name      string
+		signature []byte
+		ref       string
+		digest    digest.Digest
+		wantErr   string
+	}{
+		{
+			name:      "exact tag",
+			signature: signature,
+			ref:       "quay.io/redhat-cop/dotnet-example:latest",
+			digest:    manifestDigest,
+		},
+		{
+			name:      "digest of the same repository",
+			signature: signature,
+			ref:       "quay.io/redhat-cop/dotnet-example@" + manifestDigest.String(),
+			digest:    manifestDigest,
+		},
+		{
+			name:      "untrusted key",
+			signature: untrusted,
+			ref:       "quay.io/redhat-cop/dotnet-example:latest",
+			digest:    manifestDigest,
+			wantErr:   "Signature was not made by a trusted key",
+		},
+		{
+			name:      "wrong digest",
+			signature: signature,
+			ref:       "quay.io/redhat-cop/dotnet-example:latest",
+			digest:    otherDigest,
+			wantErr:   "Signature is for digest '" + manifestDigest.String() + "' rather than '" + otherDigest.String() + "'",
+		},
+		{
+			name:      "digest of another repository",
+			signature: signature,
+			ref:       "quay.io/redhat-cop/other-example@" + manifestDigest.String(),
+			digest:    manifestDigest,
+			wantErr:   "Signature is for repository 'quay.io/redhat-cop/dotnet-example' rather than 'quay.io/redhat-cop/other-example'",
+		},
+		{
+			name:      "other tag",
+			signature: signature,
+			ref:       "quay.io/redhat-cop/dotnet-example:v1",
+			digest:    manifestDigest,
+			wantErr:   "Signature is for 'quay.io/redhat-cop/dotnet-example:latest' rather than 'quay.io/redhat-cop/dotnet-example:v1'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			_, err := Verify(test.signature, trusted, parse(t, test.ref), test.digest)
+
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("Verify() error = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyTamperedBody(t *testing.T) {
+
+	entity := newECDSAEntity(t, "release@example.com")
+	keyring := openpgp.EntityList{entity}
+
+	signature := signAs(t, keyring, "release@example.com", "quay.io/redhat-cop/dotnet-example:latest")
+
+	// The payload is stored as literal data, so the claimed digest can be swapped in place
+	tampered := bytes.Replace(signature, []byte(manifestDigest.String()), []byte(otherDigest.String()), 1)
+	if bytes.Equal(tampered, signature) {
+		t.Fatalf("payload not found in signature")
+	}
+
+	// The signature of the body is only checked once the body has been read in full
+	message, err := openpgp.ReadMessage(bytes.NewReader(tampered), keyring, nil, nil)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if message.SignatureError != nil {
+		t.Fatalf("SignatureError set before the body was read: %v", message.SignatureError)
+	}
+	if _, err := ioutil.ReadAll(message.UnverifiedBody); err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if message.SignatureError == nil {
+		t.Fatalf("SignatureError not set once the body was read")
+	}
+
+	_, err = Verify(tampered, keyring, parse(t, "quay.io/redhat-cop/dotnet-example:latest"), otherDigest)
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid signature: ") {
+		t.Errorf("Verify() error = %v, want an invalid signature", err)
+	}
+}
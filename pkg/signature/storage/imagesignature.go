@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	imagev1 "github.com/openshift/api/image/v1"
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageSignatureTypeAtomic is the type of ImageSignature holding an atomic container signature
+const ImageSignatureTypeAtomic = "atomic"
+
+// ImageSignatures reads and writes signatures through the OpenShift image API, where they are attached to the
+// Image object named by the manifest digest. The integrated registry serves these signatures through its
+// signature extension API.
+type ImageSignatures struct {
+	client *imageset.ImageV1Client
+}
+
+// NewImageSignatures returns a store backed by the ImageSignatures of the cluster
+func NewImageSignatures(client *imageset.ImageV1Client) *ImageSignatures {
+	return &ImageSignatures{client: client}
+}
+
+// Signatures returns the content of every atomic ImageSignature of the Image
+func (s *ImageSignatures) Signatures(ref reference.Named, manifestDigest digest.Digest) ([][]byte, error) {
+
+	image, err := s.client.Images().Get(manifestDigest.String(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return [][]byte{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := [][]byte{}
+	for _, signature := range image.Signatures {
+		if signature.Type == ImageSignatureTypeAtomic {
+			signatures = append(signatures, signature.Content)
+		}
+	}
+
+	return signatures, nil
+}
+
+// Put creates an ImageSignature for the Image. The Image must already be known to the cluster, which is the case
+// for every image referenced by an ImageStream.
+func (s *ImageSignatures) Put(ref reference.Named, manifestDigest digest.Digest, signature []byte) error {
+
+	existing, err := s.Signatures(ref, manifestDigest)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range existing {
+		if bytes.Equal(candidate, signature) {
+			return nil
+		}
+	}
+
+	name, err := imageSignatureName(manifestDigest)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.ImageSignatures().Create(&imagev1.ImageSignature{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Type:       ImageSignatureTypeAtomic,
+		Content:    signature,
+	})
+	if k8serrors.IsNotFound(err) {
+		return fmt.Errorf("Image '%s' is not known to the cluster, only images referenced by an ImageStream can hold ImageSignatures", manifestDigest)
+	}
+	if err != nil {
+		return fmt.Errorf("Error creating ImageSignature for Image '%s': %v", manifestDigest, err)
+	}
+
+	return nil
+}
+
+// imageSignatureName returns a unique name for a signature of an image, which the image API and the signature
+// extension API require to be '<image name>@<32 characters>'
+func imageSignatureName(manifestDigest digest.Digest) (string, error) {
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s@%s", manifestDigest, hex.EncodeToString(suffix)), nil
+}
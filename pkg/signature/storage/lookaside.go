@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// maxSignatures bounds the number of signatures read for a single image
+const maxSignatures = 100
+
+// Lookaside reads and writes signatures in a sigstore using the lookaside layout written by 'podman image sign -d':
+// <base>/<repository path>@<algorithm>=<hex>/signature-<n>. The base may be a file:// or http(s):// URL.
+type Lookaside struct {
+	base *url.URL
+	http *http.Client
+}
+
+// NewLookaside returns a reader for the sigstore at base
+func NewLookaside(base string) (*Lookaside, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(base, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid sigstore URL '%s': %v", base, err)
+	}
+
+	switch parsed.Scheme {
+	case "file", "http", "https":
+	default:
+		return nil, fmt.Errorf("Unsupported sigstore URL '%s', expected a file, http or https URL", base)
+	}
+
+	return &Lookaside{base: parsed, http: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// Location returns the relative location of a signature of an image within a lookaside sigstore
+func Location(ref reference.Named, manifestDigest digest.Digest, index int) string {
+	return fmt.Sprintf("%s@%s=%s/signature-%d", reference.Path(ref), manifestDigest.Algorithm(), manifestDigest.Hex(), index)
+}
+
+// Signatures returns every signature stored for the manifest digest of an image
+func (l *Lookaside) Signatures(ref reference.Named, manifestDigest digest.Digest) ([][]byte, error) {
+	signatures := [][]byte{}
+
+	for index := 1; index <= maxSignatures; index++ {
+		signature, found, err := l.read(Location(ref, manifestDigest, index))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+		signatures = append(signatures, signature)
+	}
+
+	return signatures, nil
+}
+
+// Put writes a signature to the next free index of a file:// sigstore. Writing a signature that is already
+// stored has no effect.
+func (l *Lookaside) Put(ref reference.Named, manifestDigest digest.Digest, signature []byte) error {
+
+	if l.base.Scheme != "file" {
+		return fmt.Errorf("Signatures can only be written to file sigstores, not '%s'", l.base.String())
+	}
+
+	for index := 1; index <= maxSignatures; index++ {
+		path := filepath.Join(l.base.Path, filepath.FromSlash(Location(ref, manifestDigest, index)))
+
+		existing, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			return ioutil.WriteFile(path, signature, 0644)
+		}
+		if err != nil {
+			return err
+		}
+
+		if bytes.Equal(existing, signature) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Image '%s' already has %d signatures", ref.String(), maxSignatures)
+}
+
+func (l *Lookaside) read(location string) ([]byte, bool, error) {
+
+	if l.base.Scheme == "file" {
+		signature, err := ioutil.ReadFile(filepath.Join(l.base.Path, filepath.FromSlash(location)))
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return signature, err == nil, err
+	}
+
+	resp, err := l.http.Get(l.base.String() + "/" + location)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("Sigstore '%s' returned '%s' for '%s'", l.base.Host, resp.Status, location)
+	}
+
+	signature, err := ioutil.ReadAll(resp.Body)
+	return signature, err == nil, err
+}
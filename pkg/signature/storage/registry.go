@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/redhat-cop/image-security/pkg/registry"
+)
+
+// RegistryExtension reads and writes signatures through the signature extension API of the registry hosting the
+// image, as implemented by the OpenShift integrated registry and Quay: /extensions/v2/<repository>/signatures/<digest>
+type RegistryExtension struct {
+	client *registry.Client
+}
+
+// NewRegistryExtension returns a store using the client to access the registries of images
+func NewRegistryExtension(client *registry.Client) *RegistryExtension {
+	return &RegistryExtension{client: client}
+}
+
+// Signatures returns the content of every atomic signature stored by the registry for the image
+func (s *RegistryExtension) Signatures(ref reference.Named, manifestDigest digest.Digest) ([][]byte, error) {
+
+	stored, err := s.client.GetSignatures(ref, manifestDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := [][]byte{}
+	for _, signature := range stored {
+		if signature.Type == ImageSignatureTypeAtomic {
+			signatures = append(signatures, signature.Content)
+		}
+	}
+
+	return signatures, nil
+}
+
+// Put stores a signature with the registry, which must advertise support for the signature extension API
+func (s *RegistryExtension) Put(ref reference.Named, manifestDigest digest.Digest, signature []byte) error {
+
+	supported, err := s.client.SupportsSignatures(ref)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return fmt.Errorf("Registry '%s' does not support the signature extension API", reference.Domain(ref))
+	}
+
+	existing, err := s.Signatures(ref, manifestDigest)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range existing {
+		if bytes.Equal(candidate, signature) {
+			return nil
+		}
+	}
+
+	name, err := imageSignatureName(manifestDigest)
+	if err != nil {
+		return err
+	}
+
+	return s.client.PutSignature(ref, manifestDigest, name, ImageSignatureTypeAtomic, signature)
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"github.com/docker/distribution/reference"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// Store reads and writes the signatures of images
+type Store interface {
+	// Signatures returns every signature stored for the manifest digest of an image
+	Signatures(ref reference.Named, manifestDigest digest.Digest) ([][]byte, error)
+	// Put stores a signature of the manifest digest of an image. Storing a signature that is already stored has
+	// no effect.
+	Put(ref reference.Named, manifestDigest digest.Digest, signature []byte) error
+}
+
+var _ Store = &Lookaside{}
+var _ Store = &ImageSignatures{}
+var _ Store = &RegistryExtension{}
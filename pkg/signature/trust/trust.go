@@ -0,0 +1,88 @@
+package trust
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrNoTrustedKeys is returned when no key is trusted to sign images
+var ErrNoTrustedKeys = errors.New("No trusted signing keys are configured")
+
+// trustedKey is a public key along with where it was read from
+type trustedKey struct {
+	source string
+	key    []byte
+}
+
+// Keyring returns the public keys trusted to sign images. These are the operator's default signing key, every
+// key listed in the trusted keys ConfigMap in the target project and the current and retained keys of the
+// SigningKeys in the target project. SigningKeys of other namespaces are never trusted, as anyone able to create one
+// there controls the key it signs with. Keys that cannot be read are skipped, ErrNoTrustedKeys is returned when none
+// remain.
+func Keyring(c client.Client, configuration config.Config) (openpgp.EntityList, error) {
+	keys := []trustedKey{}
+
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: configuration.GpgSecret, Namespace: configuration.TargetProject}, secret)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		keys = append(keys, trustedKey{source: fmt.Sprintf("Secret '%s'", configuration.GpgSecret), key: secret.Data[common.GpgPublicKeyringKey]})
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: configuration.TrustedKeysConfigMap, Namespace: configuration.TargetProject}, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	for name, key := range configMap.Data {
+		keys = append(keys, trustedKey{source: fmt.Sprintf("ConfigMap '%s' Key '%s'", configMap.Name, name), key: []byte(key)})
+	}
+	for name, key := range configMap.BinaryData {
+		keys = append(keys, trustedKey{source: fmt.Sprintf("ConfigMap '%s' Key '%s'", configMap.Name, name), key: key})
+	}
+
+	signingKeys := &v1alpha2.SigningKeyList{}
+	if err := c.List(context.TODO(), signingKeys, client.InNamespace(configuration.TargetProject)); err != nil {
+		return nil, err
+	}
+	for _, signingKey := range signingKeys.Items {
+		source := fmt.Sprintf("SigningKey '%s'", signingKey.Name)
+		if signingKey.Status.CurrentKey != nil {
+			keys = append(keys, trustedKey{source: source, key: []byte(signingKey.Status.CurrentKey.PublicKey)})
+		}
+		for _, previous := range signingKey.Status.PreviousKeys {
+			keys = append(keys, trustedKey{source: source, key: []byte(previous.PublicKey)})
+		}
+	}
+
+	// A key that cannot be read must not prevent images signed by the other keys from being verified
+	keyring := openpgp.EntityList{}
+	for _, trusted := range keys {
+		entities, err := signature.Keyring(trusted.key)
+		if err != nil {
+			logrus.Warnf("Ignoring Unreadable Public Key of %s: %v", trusted.source, err)
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, ErrNoTrustedKeys
+	}
+
+	return keyring, nil
+}
@@ -0,0 +1,83 @@
+package sigstore
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// shutdownTimeout bounds how long in flight requests are given to complete when the manager stops
+const shutdownTimeout = 10 * time.Second
+
+// signatureFile matches the name of the files holding signatures within the lookaside layout
+var signatureFile = regexp.MustCompile(`^signature-[0-9]+$`)
+
+// Add runs the sigstore server with the Manager when SIGSTORE_SERVER_ADDRESS is set
+func Add(mgr manager.Manager) error {
+	configuration := config.LoadConfig()
+
+	if configuration.SigstoreServerAddress == "" {
+		return nil
+	}
+
+	return mgr.Add(NewServer(configuration.SigstoreServerAddress, configuration.SigstoreDir))
+}
+
+// Server serves the signatures of a sigstore directory over HTTP in the lookaside layout read by containers/image:
+// <repository path>@<algorithm>=<hex>/signature-<n>. Only signature files are served, directories are never listed.
+type Server struct {
+	address string
+	dir     string
+}
+
+var _ manager.Runnable = &Server{}
+
+// NewServer returns a server listening on address for the signatures in dir
+func NewServer(address string, dir string) *Server {
+	return &Server{address: address, dir: dir}
+}
+
+// Start serves signatures until the stop channel is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	server := &http.Server{Addr: s.address, Handler: s}
+
+	errs := make(chan error, 1)
+	go func() {
+		logrus.Infof("Serving Sigstore '%s' on '%s'", s.dir, s.address)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	case err := <-errs:
+		return err
+	}
+}
+
+// ServeHTTP returns the signature at the path of the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	location := path.Clean("/" + r.URL.Path)
+	if !signatureFile.MatchString(path.Base(location)) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeFile(w, r, filepath.Join(s.dir, filepath.FromSlash(location)))
+}
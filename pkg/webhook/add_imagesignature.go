@@ -0,0 +1,10 @@
+package webhook
+
+import (
+	"github.com/redhat-cop/image-security/pkg/webhook/imagesignature"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create webhooks and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, imagesignature.Add)
+}
@@ -0,0 +1,16 @@
+package conversion
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// Path is where the API server sends ConversionReview requests for the operator's CRDs
+const Path = "/convert"
+
+// Add registers the CRD conversion webhook with the webhook server of the Manager. Types are converted
+// through their Hub version using the Convertible implementations registered in the Manager's scheme.
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(Path, &conversion.Webhook{})
+	return nil
+}
@@ -0,0 +1,337 @@
+package imagesignature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	imageset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/redhat-cop/image-security/pkg/controller/common"
+	"github.com/redhat-cop/image-security/pkg/controller/config"
+	"github.com/redhat-cop/image-security/pkg/registry"
+	"github.com/redhat-cop/image-security/pkg/signature"
+	"github.com/redhat-cop/image-security/pkg/signature/storage"
+	"github.com/redhat-cop/image-security/pkg/signature/trust"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is where the API server sends AdmissionReview requests for Pods and workloads
+const Path = "/validate-image-signatures"
+
+// VerificationModeLabel opts a namespace in to signature verification. Its value selects the mode.
+const VerificationModeLabel = "cop.redhat.com/image-signature-verification"
+
+const (
+	// ModeEnforce rejects workloads using images without a valid signature
+	ModeEnforce = "enforce"
+	// ModeWarn admits workloads using images without a valid signature and records a warning event
+	ModeWarn = "warn"
+	// ModeAudit admits workloads using images without a valid signature and records the result in the audit log
+	ModeAudit = "audit"
+)
+
+// unsignedImagesAuditAnnotation is recorded in the audit log of admitted requests with unsigned images
+const unsignedImagesAuditAnnotation = "unsigned-images"
+
+// podSpecPaths locates the pod spec within each kind of workload reviewed by the webhook
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+	"DeploymentConfig":      {"spec", "template", "spec"},
+}
+
+// Add registers the image signature verification webhook with the webhook server of the Manager
+func Add(mgr manager.Manager) error {
+	imageClient, err := imageset.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	mgr.GetWebhookServer().Register(Path, &webhook.Admission{Handler: &verifier{
+		config:      config.LoadConfig(),
+		imageClient: imageClient,
+		recorder:    mgr.GetEventRecorderFor(common.ControllerAgentName),
+	}})
+	return nil
+}
+
+// verifier checks that every image used by a Pod or workload carries a signature from a trusted key
+type verifier struct {
+	client      client.Client
+	config      config.Config
+	imageClient *imageset.ImageV1Client
+	recorder    record.EventRecorder
+}
+
+var _ admission.Handler = &verifier{}
+
+// InjectClient is called by the Manager to provide the client used to look up keys and pull secrets
+func (v *verifier) InjectClient(c client.Client) error {
+	v.client = c
+	return nil
+}
+
+func (v *verifier) Handle(ctx context.Context, req admission.Request) admission.Response {
+
+	path, ok := podSpecPaths[req.Kind.Kind]
+	if !ok {
+		return admission.Allowed("")
+	}
+
+	object, podSpec, err := decodePodSpec(req.Object.Raw, path)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if podSpec == nil {
+		return admission.Allowed("")
+	}
+
+	// Updates that keep the images, such as scaling or changing labels, are not verified again so that workloads
+	// admitted before keep working when the registry or sigstore cannot be reached
+	if req.Operation == admissionv1beta1.Update && len(req.OldObject.Raw) > 0 {
+		_, oldPodSpec, err := decodePodSpec(req.OldObject.Raw, path)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if oldPodSpec != nil && reflect.DeepEqual(podSpecImages(oldPodSpec), podSpecImages(podSpec)) {
+			return admission.Allowed("Images are unchanged")
+		}
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: req.Namespace}, namespace); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	mode := namespace.Labels[VerificationModeLabel]
+	if mode != ModeEnforce && mode != ModeWarn && mode != ModeAudit {
+		return admission.Allowed("")
+	}
+
+	problems, err := v.verifyPodSpec(ctx, req.Namespace, podSpec)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if len(problems) == 0 {
+		return admission.Allowed("All images are signed by a trusted key")
+	}
+
+	name := object.GetName()
+	if name == "" {
+		name = object.GetGenerateName()
+	}
+
+	message := fmt.Sprintf("%s '%s' uses images without a valid signature: %s", req.Kind.Kind, name, strings.Join(problems, "; "))
+
+	switch mode {
+	case ModeEnforce:
+		return admission.Denied(message)
+	case ModeWarn:
+		v.recorder.Event(eventTarget(object, namespace), corev1.EventTypeWarning, "UnsignedImage", message)
+	}
+
+	logrus.Infof("Admitting in %s mode: %s", mode, message)
+
+	response := admission.Allowed(message)
+	response.AuditAnnotations = map[string]string{unsignedImagesAuditAnnotation: strings.Join(problems, "; ")}
+	return response
+}
+
+// decodePodSpec reads a Pod or workload along with its pod spec, which is nil when the object has none. Workloads are
+// read generically so every kind shares the same path to its pod spec.
+func decodePodSpec(raw []byte, path []string) (*unstructured.Unstructured, *corev1.PodSpec, error) {
+
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal(raw, &object.Object); err != nil {
+		return nil, nil, err
+	}
+
+	field, found, err := unstructured.NestedMap(object.Object, path...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return object, nil, nil
+	}
+
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(field, podSpec); err != nil {
+		return nil, nil, err
+	}
+
+	return object, podSpec, nil
+}
+
+// podSpecImages lists the images of the init containers and containers of a pod spec in order
+func podSpecImages(podSpec *corev1.PodSpec) []string {
+	images := []string{}
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		images = append(images, container.Image)
+	}
+
+	return images
+}
+
+// eventTarget returns the object warnings about a reviewed object are recorded on. The reviewed object does not exist
+// yet and may only have a generateName, so the warning is recorded on its owner, or on its namespace when it has none.
+func eventTarget(object *unstructured.Unstructured, namespace *corev1.Namespace) runtime.Object {
+
+	owners := object.GetOwnerReferences()
+	if len(owners) == 0 {
+		return namespace
+	}
+
+	return &corev1.ObjectReference{
+		APIVersion: owners[0].APIVersion,
+		Kind:       owners[0].Kind,
+		Name:       owners[0].Name,
+		UID:        owners[0].UID,
+		Namespace:  namespace.Name,
+	}
+}
+
+// verifyPodSpec returns a description of every image in the pod spec without a valid signature
+func (v *verifier) verifyPodSpec(ctx context.Context, namespace string, podSpec *corev1.PodSpec) ([]string, error) {
+
+	// Missing keys are reported like unsigned images, so that the mode of the namespace decides whether to admit
+	keyring, err := trust.Keyring(v.client, v.config)
+	if err == trust.ErrNoTrustedKeys {
+		return []string{err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	images := []string{}
+	seen := map[string]bool{}
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		if !seen[container.Image] {
+			seen[container.Image] = true
+			images = append(images, container.Image)
+		}
+	}
+
+	registryClient := registry.NewClient(v.pullCredentials(ctx, namespace, podSpec), v.config.RegistryTLSVerify)
+
+	var store storage.Store
+	switch v.config.SignatureStorage {
+	case config.SignatureStorageImageSignature:
+		store = storage.NewImageSignatures(v.imageClient)
+	case config.SignatureStorageRegistry:
+		store = storage.NewRegistryExtension(registryClient)
+	default:
+		sigstoreURL := v.config.SigstoreURL
+		if sigstoreURL == "" && v.config.SignatureStorage == config.SignatureStorageLookaside {
+			sigstoreURL = "file://" + v.config.SigstoreDir
+		}
+
+		if sigstoreURL == "" {
+			return []string{"No sigstore is configured to read signatures from"}, nil
+		}
+
+		store, err = storage.NewLookaside(sigstoreURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	problems := []string{}
+	for _, image := range images {
+		if err := verifyImage(registryClient, store, keyring, image); err != nil {
+			problems = append(problems, fmt.Sprintf("'%s': %v", image, err))
+		}
+	}
+
+	return problems, nil
+}
+
+func verifyImage(registryClient *registry.Client, store storage.Store, keyring openpgp.EntityList, image string) error {
+
+	ref, err := registry.ParseReference(image)
+	if err != nil {
+		return err
+	}
+
+	manifestDigest, _, err := registryClient.ResolveDigest(ref)
+	if err != nil {
+		return err
+	}
+
+	signatures, err := store.Signatures(ref, manifestDigest)
+	if err != nil {
+		return err
+	}
+
+	if len(signatures) == 0 {
+		return fmt.Errorf("No signatures found for digest '%s'", manifestDigest)
+	}
+
+	for _, candidate := range signatures {
+		if _, err = signature.Verify(candidate, keyring, ref, manifestDigest); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// pullCredentials collects the credentials of the image pull secrets of the pod and of its service account, which
+// is how the kubelet would authenticate when pulling the images
+func (v *verifier) pullCredentials(ctx context.Context, namespace string, podSpec *corev1.PodSpec) registry.Credentials {
+
+	pullSecrets := append([]corev1.LocalObjectReference{}, podSpec.ImagePullSecrets...)
+
+	serviceAccountName := podSpec.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = "default"
+	}
+
+	serviceAccount := &corev1.ServiceAccount{}
+	err := v.client.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
+	if err == nil {
+		pullSecrets = append(pullSecrets, serviceAccount.ImagePullSecrets...)
+	} else if !k8serrors.IsNotFound(err) {
+		logrus.Warnf("Error retrieving ServiceAccount '%s' in Namespace '%s': %v", serviceAccountName, namespace, err)
+	}
+
+	credentials := registry.Credentials{}
+	for _, pullSecret := range pullSecrets {
+		secret := &corev1.Secret{}
+		if err := v.client.Get(ctx, types.NamespacedName{Name: pullSecret.Name, Namespace: namespace}, secret); err != nil {
+			logrus.Warnf("Error retrieving pull secret '%s' in Namespace '%s': %v", pullSecret.Name, namespace, err)
+			continue
+		}
+
+		secretCredentials, err := registry.CredentialsFromSecret(secret)
+		if err != nil {
+			logrus.Warnf(err.Error())
+			continue
+		}
+
+		credentials.Merge(secretCredentials)
+	}
+
+	return credentials
+}
@@ -0,0 +1,76 @@
+package imagesignature
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+)
+
+func TestEventTargetOfGenerateNamePod(t *testing.T) {
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dotnet-example", UID: "namespace-uid"}}
+
+	tests := []struct {
+		name          string
+		pod           string
+		wantKind      string
+		wantName      string
+		wantNamespace string
+	}{
+		{
+			name: "owned by a ReplicaSet",
+			pod: `{"apiVersion": "v1", "kind": "Pod", "metadata": {"generateName": "frontend-5d8f7b6c9-", "namespace": "dotnet-example",
+				"ownerReferences": [{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "frontend-5d8f7b6c9", "uid": "replicaset-uid", "controller": true}]},
+				"spec": {"containers": [{"name": "frontend", "image": "quay.io/redhat-cop/frontend:latest"}]}}`,
+			wantKind:      "ReplicaSet",
+			wantName:      "frontend-5d8f7b6c9",
+			wantNamespace: "dotnet-example",
+		},
+		{
+			name: "without an owner",
+			pod: `{"apiVersion": "v1", "kind": "Pod", "metadata": {"generateName": "debug-", "namespace": "dotnet-example"},
+				"spec": {"containers": [{"name": "debug", "image": "quay.io/redhat-cop/debug:latest"}]}}`,
+			wantKind: "Namespace",
+			wantName: "dotnet-example",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			object, podSpec, err := decodePodSpec([]byte(test.pod), podSpecPaths["Pod"])
+			if err != nil {
+				t.Fatalf("decodePodSpec() error = %v", err)
+			}
+			if podSpec == nil {
+				t.Fatalf("decodePodSpec() found no pod spec")
+			}
+
+			target := eventTarget(object, namespace)
+
+			ref, err := reference.GetReference(scheme.Scheme, target)
+			if err != nil {
+				t.Fatalf("GetReference() error = %v", err)
+			}
+
+			if ref.Kind != test.wantKind || ref.Name != test.wantName || ref.Namespace != test.wantNamespace {
+				t.Errorf("event recorded on %s '%s/%s', want %s '%s/%s'", ref.Kind, ref.Namespace, ref.Name, test.wantKind, test.wantNamespace, test.wantName)
+			}
+
+			// The recorder names events after the object they are recorded on, which must not be empty
+			if ref.Name == "" {
+				t.Errorf("event recorded on an object without a name")
+			}
+
+			recorder := record.NewFakeRecorder(1)
+			recorder.Event(target, corev1.EventTypeWarning, "UnsignedImage", "unsigned")
+			if len(recorder.Events) != 1 {
+				t.Errorf("no event recorded")
+			}
+		})
+	}
+}
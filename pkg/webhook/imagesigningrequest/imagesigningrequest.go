@@ -0,0 +1,129 @@
+package imagesigningrequest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
+	"github.com/redhat-cop/image-security/pkg/controller/imagesigningrequest/signing"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Path is where the API server sends AdmissionReview requests for ImageSigningRequests
+const Path = "/validate-imagesigningrequest"
+
+// Add registers the ImageSigningRequest validating webhook with the webhook server of the Manager
+func Add(mgr manager.Manager) error {
+	mgr.GetWebhookServer().Register(Path, &webhook.Admission{Handler: &validator{}})
+	return nil
+}
+
+// validator rejects ImageSigningRequests that could never be signed and changes to the spec of a request
+// that has already been acted upon
+type validator struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &validator{}
+
+// InjectClient is called by the Manager to provide the client used to look up referenced secrets
+func (v *validator) InjectClient(c client.Client) error {
+	v.client = c
+	return nil
+}
+
+// InjectDecoder is called by the webhook to provide the decoder for the objects under review
+func (v *validator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+
+	instance := &v1alpha2.ImageSigningRequest{}
+	if err := v.decoder.Decode(req, instance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1beta1.Update {
+		existing := &v1alpha2.ImageSigningRequest{}
+		if err := v.decoder.DecodeRaw(req.OldObject, existing); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		if existing.Status.Phase != "" {
+			if !equality.Semantic.DeepEqual(existing.Spec, instance.Spec) {
+				return admission.Denied(fmt.Sprintf("The spec of ImageSigningRequest '%s' cannot be changed once it is %s", req.Name, existing.Status.Phase))
+			}
+			return admission.Allowed("")
+		}
+
+		// Metadata changes do not alter what would be signed
+		if equality.Semantic.DeepEqual(existing.Spec, instance.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	problems, err := v.validate(ctx, req.Namespace, instance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if len(problems) > 0 {
+		return admission.Denied(fmt.Sprintf("Invalid ImageSigningRequest '%s': %s", req.Name, strings.Join(problems, "; ")))
+	}
+
+	return admission.Allowed("")
+}
+
+// validate returns a description of every problem with the spec of the request
+func (v *validator) validate(ctx context.Context, namespace string, instance *v1alpha2.ImageSigningRequest) ([]string, error) {
+	problems := signing.ValidateSpec(instance.Spec)
+
+	if instance.Spec.SigningKeySecretName != "" {
+		found, err := v.exists(ctx, types.NamespacedName{Name: instance.Spec.SigningKeySecretName, Namespace: namespace}, &corev1.Secret{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("GPG Secret '%s' Not Found in Namespace '%s'", instance.Spec.SigningKeySecretName, namespace))
+		}
+	}
+
+	if instance.Spec.SigningKeyRef != nil {
+		found, err := v.exists(ctx, types.NamespacedName{Name: instance.Spec.SigningKeyRef.Name, Namespace: namespace}, &v1alpha2.SigningKey{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("SigningKey '%s' Not Found in Namespace '%s'", instance.Spec.SigningKeyRef.Name, namespace))
+		}
+	}
+
+	return problems, nil
+}
+
+func (v *validator) exists(ctx context.Context, key types.NamespacedName, obj runtime.Object) (bool, error) {
+	err := v.client.Get(ctx, key, obj)
+
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
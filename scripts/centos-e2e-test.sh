@@ -13,9 +13,14 @@ oc apply -f test/e2e/deploy/service_account.yaml
 oc apply -f test/e2e/deploy/role.yaml
 oc apply -f test/e2e/deploy/role_binding.yaml
 oc apply -f test/e2e/deploy/secret.yaml
+oc apply -f test/e2e/deploy/webhook_service.yaml
 oc apply -f test/e2e/deploy/operator.yaml
 oc rollout status deployment image-security --watch
 
+# Serve v1alpha1 ImageSigningRequests through the conversion webhook of the operator under test
+oc patch crd imagesigningrequests.imagesigningrequests.cop.redhat.com --type=json \
+  -p '[{"op": "replace", "path": "/spec/conversion/webhookClientConfig/service/namespace", "value": "image-management-test"}]'
+
 # Create namespace that will hold the image to be signed
 # The E2E test will fail if this namespace and image are not present on the system
 oc new-project signing-test
@@ -26,6 +31,10 @@ oc rollout status deploymentconfig dotnet-example --watch
 # Run the E2E test
 operator-sdk test local ./test/e2e/centos --namespace "image-management-test" --no-setup
 
+# Point the conversion webhook back at the installed operator
+oc patch crd imagesigningrequests.imagesigningrequests.cop.redhat.com --type=json \
+  -p '[{"op": "replace", "path": "/spec/conversion/webhookClientConfig/service/namespace", "value": "image-management"}]'
+
 # Remove testing namespaces
  oc delete project/signing-test
  oc delete project/image-management-test
\ No newline at end of file
@@ -0,0 +1,75 @@
+package e2e
+
+import (
+	goctx "context"
+	"fmt"
+	"testing"
+
+	framework "github.com/operator-framework/operator-sdk/pkg/test"
+	imagescanningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagescanningrequests/v1alpha1"
+	"github.com/redhat-cop/image-security/pkg/controller/images"
+	util "github.com/redhat-cop/image-security/test/e2e"
+	"github.com/stretchr/testify/assert"
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestCentosScanning(t *testing.T) {
+	ctx := framework.NewTestCtx(t)
+	defer ctx.Cleanup()
+	util.AddScanningToFrameworkSchemeForTests(t, ctx)
+	centosScanning(t, framework.Global, ctx)
+}
+
+func centosScanning(t *testing.T, f *framework.Framework, ctx *framework.TestCtx) {
+	namespace, err := ctx.GetNamespace()
+	assert.NoError(t, err)
+
+	imageScanningRequest := &imagescanningrequestsv1alpha1.ImageScanningRequest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ImageScanningRequest",
+			APIVersion: "imagescanningrequests.cop.redhat.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.CentosScanName,
+			Namespace: util.ImageNamespace,
+		},
+		Spec: imagescanningrequestsv1alpha1.ImageScanningRequestSpec{
+			ContainerImage: &kapi.ObjectReference{
+				Kind: "ImageStreamTag",
+				Name: "dotnet-example:latest",
+			},
+		},
+	}
+
+	err = f.Client.Create(goctx.TODO(), imageScanningRequest, &framework.CleanupOptions{TestContext: ctx, Timeout: util.Timeout, RetryInterval: util.RetryInterval})
+	assert.NoError(t, err)
+
+	// Check if the CR has been created and has no scanned image details
+	cr := &imagescanningrequestsv1alpha1.ImageScanningRequest{}
+	err = f.Client.Get(goctx.TODO(), types.NamespacedName{Name: util.CentosScanName, Namespace: util.ImageNamespace}, cr)
+	assert.NoError(t, err)
+	assert.Empty(t, cr.Status.ScannedImage)
+
+	// Check for a pod with the correct annotation
+	success := util.WaitForPodWithImageCompleted(t, f, ctx, namespace, "cop.redhat.com/owner", "signing-test/dotnet-app-scan", util.CentosImage, util.RetryInterval, util.Timeout)
+	assert.NoError(t, success)
+
+	// Need to wait for the pod controller to pick up the status change of the pod
+	err = wait.Poll(util.RetryInterval, util.StatusTimeout, func() (done bool, err error) {
+		cr := &imagescanningrequestsv1alpha1.ImageScanningRequest{}
+		err = f.Client.Get(goctx.TODO(), types.NamespacedName{Name: util.CentosScanName, Namespace: util.ImageNamespace}, cr)
+		if err != nil {
+			return false, fmt.Errorf("CR not found")
+		}
+		if cr.Status.Phase == images.PhaseCompleted {
+			assert.NotEmpty(t, cr.Status.ScannedImage)
+			assert.NotNil(t, cr.Status.Results)
+			return true, nil
+		}
+		return false, nil
+	})
+	assert.NoError(t, err)
+}
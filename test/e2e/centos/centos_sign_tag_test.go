@@ -6,7 +6,7 @@ import (
 	"testing"
 
 	framework "github.com/operator-framework/operator-sdk/pkg/test"
-	imagesigningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha1"
+	imagesigningrequestsv1alpha2 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
 	util "github.com/redhat-cop/image-security/test/e2e"
 	"github.com/stretchr/testify/assert"
 	kapi "k8s.io/api/core/v1"
@@ -26,16 +26,16 @@ func centosSigning(t *testing.T, f *framework.Framework, ctx *framework.TestCtx)
 	namespace, err := ctx.GetNamespace()
 	assert.NoError(t, err)
 
-	imageSigningRequest := &imagesigningrequestsv1alpha1.ImageSigningRequest{
+	imageSigningRequest := &imagesigningrequestsv1alpha2.ImageSigningRequest{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ImageSigningRequest",
-			APIVersion: "imagesigningrequests.cop.redhat.com/v1alpha1",
+			APIVersion: "imagesigningrequests.cop.redhat.com/v1alpha2",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      util.CentosTagName,
 			Namespace: util.ImageNamespace,
 		},
-		Spec: imagesigningrequestsv1alpha1.ImageSigningRequestSpec{
+		Spec: imagesigningrequestsv1alpha2.ImageSigningRequestSpec{
 			ContainerImage: &kapi.ObjectReference{
 				Kind: "ImageStreamTag",
 				Name: "dotnet-example:latest",
@@ -48,7 +48,7 @@ func centosSigning(t *testing.T, f *framework.Framework, ctx *framework.TestCtx)
 	assert.Empty(t, err)
 
 	// Check if the CR has been created and has no signed image details
-	cr := &imagesigningrequestsv1alpha1.ImageSigningRequest{}
+	cr := &imagesigningrequestsv1alpha2.ImageSigningRequest{}
 	err = f.Client.Get(goctx.TODO(), types.NamespacedName{Name: util.CentosTagName, Namespace: util.ImageNamespace}, cr)
 	assert.NoError(t, err)
 	assert.Empty(t, cr.Status.SignedImage)
@@ -60,7 +60,7 @@ func centosSigning(t *testing.T, f *framework.Framework, ctx *framework.TestCtx)
 	// Need to wait for the pod controller to pick up the status change of the pod
 	err = wait.Poll(util.RetryInterval, util.StatusTimeout, func() (done bool, err error) {
 		// Verify the CR has been signing details
-		cr := &imagesigningrequestsv1alpha1.ImageSigningRequest{}
+		cr := &imagesigningrequestsv1alpha2.ImageSigningRequest{}
 		err = f.Client.Get(goctx.TODO(), types.NamespacedName{Name: util.CentosTagName, Namespace: util.ImageNamespace}, cr)
 		if err != nil {
 			return false, fmt.Errorf("CR not found")
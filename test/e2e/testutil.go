@@ -8,7 +8,9 @@ import (
 
 	framework "github.com/operator-framework/operator-sdk/pkg/test"
 	"github.com/redhat-cop/image-security/pkg/apis"
+	imagescanningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagescanningrequests/v1alpha1"
 	imagesigningrequestsv1alpha1 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha1"
+	imagesigningrequestsv1alpha2 "github.com/redhat-cop/image-security/pkg/apis/imagesigningrequests/v1alpha2"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -24,6 +26,8 @@ var (
 	CleanupRetryInterval = time.Second * 1
 	CleanupTimeout       = time.Second * 5
 	CentosTagName        = "dotnet-app"
+	CentosV1alpha1Name   = "v1alpha1-dotnet-app"
+	CentosScanName       = "dotnet-app-scan"
 	SigningRemoteName    = "signing-app"
 	ImageNamespace       = "signing-test" // Namespace that the image to scan exists in
 	CentosImage          = "quay.io/cnuland/image-signing-centos8"
@@ -80,13 +84,35 @@ func AddToFrameworkSchemeForTests(t *testing.T, ctx *framework.TestCtx) {
 	namespace, err := ctx.GetNamespace()
 	assert.NoError(t, err)
 
+	imageSigningRequest := &imagesigningrequestsv1alpha2.ImageSigningRequest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ImageSigningRequest",
+			APIVersion: "imagesigningrequests.cop.redhat.com/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CentosTagName,
+			Namespace: namespace,
+		},
+		Spec:   imagesigningrequestsv1alpha2.ImageSigningRequestSpec{},
+		Status: imagesigningrequestsv1alpha2.ImageSigningRequestStatus{},
+	}
+
+	assert.NoError(t, framework.AddToFrameworkScheme(apis.AddToScheme, imageSigningRequest))
+}
+
+// AddV1alpha1ToFrameworkSchemeForTests registers the v1alpha1 ImageSigningRequest, which is served through the
+// conversion webhook of the operator
+func AddV1alpha1ToFrameworkSchemeForTests(t *testing.T, ctx *framework.TestCtx) {
+	namespace, err := ctx.GetNamespace()
+	assert.NoError(t, err)
+
 	imageSigningRequest := &imagesigningrequestsv1alpha1.ImageSigningRequest{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ImageSigningRequest",
 			APIVersion: "imagesigningrequests.cop.redhat.com/v1alpha1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      CentosTagName,
+			Name:      CentosV1alpha1Name,
 			Namespace: namespace,
 		},
 		Spec:   imagesigningrequestsv1alpha1.ImageSigningRequestSpec{},
@@ -95,3 +121,23 @@ func AddToFrameworkSchemeForTests(t *testing.T, ctx *framework.TestCtx) {
 
 	assert.NoError(t, framework.AddToFrameworkScheme(apis.AddToScheme, imageSigningRequest))
 }
+
+func AddScanningToFrameworkSchemeForTests(t *testing.T, ctx *framework.TestCtx) {
+	namespace, err := ctx.GetNamespace()
+	assert.NoError(t, err)
+
+	imageScanningRequest := &imagescanningrequestsv1alpha1.ImageScanningRequest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ImageScanningRequest",
+			APIVersion: "imagescanningrequests.cop.redhat.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CentosScanName,
+			Namespace: namespace,
+		},
+		Spec:   imagescanningrequestsv1alpha1.ImageScanningRequestSpec{},
+		Status: imagescanningrequestsv1alpha1.ImageScanningRequestStatus{},
+	}
+
+	assert.NoError(t, framework.AddToFrameworkScheme(apis.AddToScheme, imageScanningRequest))
+}